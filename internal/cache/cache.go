@@ -0,0 +1,208 @@
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+)
+
+var (
+	ErrCouldntCreateCacheDir    = errors.New("couldn't create cache directory")
+	ErrCouldntWriteCacheEntry   = errors.New("couldn't write cache entry")
+	ErrCouldntEvictCacheEntries = errors.New("couldn't evict cache entries")
+)
+
+// keyVersion is part of every key; it is to be changed whenever the format of
+// cached values changes, so that stale entries are never read.
+const keyVersion = "v1"
+
+const tmpFileSuffix = ".tmp"
+
+// only files named like keys (or being written as such) are ever removed, which
+// keeps other files in the directory safe
+var entryNameRegex = regexp.MustCompile(`^[0-9a-f]{64}(\.tmp)?$`)
+
+// Cache stores values on disk, one file per key. Entries are considered stale
+// once they are older than the maximum age, and the oldest ones are evicted
+// when the cache grows beyond its maximum size. A zero limit disables it.
+type Cache struct {
+	dir     string
+	maxAge  time.Duration
+	maxSize int64
+}
+
+func New(dir string, maxAge time.Duration, maxSize int64) (*Cache, error) {
+	err := os.MkdirAll(dir, 0o755)
+	if err != nil {
+		return nil, fmt.Errorf("%w (%q): %w", ErrCouldntCreateCacheDir, dir, err)
+	}
+
+	return &Cache{
+		dir:     dir,
+		maxAge:  maxAge,
+		maxSize: maxSize,
+	}, nil
+}
+
+// Key returns a key that identifies the given parts, in order.
+func Key(parts ...string) string {
+	hash := sha256.New()
+	hash.Write([]byte(keyVersion))
+	for _, part := range parts {
+		hash.Write([]byte{0})
+		hash.Write([]byte(part))
+	}
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// Get returns the value for a key, if present and not stale.
+func (c *Cache) Get(key string) ([]byte, bool) {
+	path := c.path(key)
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, false
+	}
+
+	if c.isStale(info, time.Now()) {
+		return nil, false
+	}
+
+	value, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+
+	return value, true
+}
+
+// Put stores the value for a key. The value is written to a temporary file
+// first, so that concurrent readers never see partially written entries.
+func (c *Cache) Put(key string, value []byte) error {
+	tmpFile, err := os.CreateTemp(c.dir, key+"-*"+tmpFileSuffix)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrCouldntWriteCacheEntry, err)
+	}
+	tmpPath := tmpFile.Name()
+
+	_, err = tmpFile.Write(value)
+	closeErr := tmpFile.Close()
+	if err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmpPath, c.path(key))
+	}
+	if err != nil {
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("%w: %w", ErrCouldntWriteCacheEntry, err)
+	}
+
+	return nil
+}
+
+// Evict removes stale entries, and then the oldest remaining ones until the
+// cache fits within its maximum size.
+func (c *Cache) Evict() error {
+	dirEntries, err := os.ReadDir(c.dir)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrCouldntEvictCacheEntries, err)
+	}
+
+	type entry struct {
+		path    string
+		size    int64
+		modTime time.Time
+	}
+
+	now := time.Now()
+	var entries []entry
+	var totalSize int64
+	for _, dirEntry := range dirEntries {
+		if !dirEntry.Type().IsRegular() || !isEntryName(dirEntry.Name()) {
+			continue
+		}
+
+		info, err := dirEntry.Info()
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrCouldntEvictCacheEntries, err)
+		}
+
+		path := filepath.Join(c.dir, dirEntry.Name())
+		if c.isStale(info, now) {
+			err := remove(path)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		// temporary files of writes that are in progress are left alone
+		if strings.HasSuffix(dirEntry.Name(), tmpFileSuffix) {
+			continue
+		}
+
+		entries = append(entries, entry{path: path, size: info.Size(), modTime: info.ModTime()})
+		totalSize += info.Size()
+	}
+
+	if c.maxSize <= 0 || totalSize <= c.maxSize {
+		return nil
+	}
+
+	slices.SortFunc(entries, func(a, b entry) int {
+		return a.modTime.Compare(b.modTime)
+	})
+
+	for _, e := range entries {
+		if totalSize <= c.maxSize {
+			break
+		}
+
+		err := remove(e.path)
+		if err != nil {
+			return err
+		}
+		totalSize -= e.size
+	}
+
+	return nil
+}
+
+func (c *Cache) path(key string) string {
+	return filepath.Join(c.dir, key)
+}
+
+func (c *Cache) isStale(info fs.FileInfo, now time.Time) bool {
+	return c.maxAge > 0 && now.Sub(info.ModTime()) > c.maxAge
+}
+
+func isEntryName(name string) bool {
+	// temporary files are named <key>-<random>.tmp
+	if key, _, ok := strings.Cut(name, "-"); ok && strings.HasSuffix(name, tmpFileSuffix) {
+		name = key + tmpFileSuffix
+	}
+
+	return entryNameRegex.MatchString(name)
+}
+
+func remove(path string) error {
+	err := os.Remove(path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("%w: %w", ErrCouldntEvictCacheEntries, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,145 @@
+package cache
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCache(t *testing.T) {
+	// SUCCESSES
+	t.Run("returns stored values", func(t *testing.T) {
+		// GIVEN
+		c, err := New(t.TempDir(), time.Hour, 0)
+		require.NoError(t, err)
+		key := Key("module_a", "1.0.0", "1.1.0", "git", "diff")
+		require.NoError(t, c.Put(key, []byte("diff output")))
+
+		// WHEN
+		value, ok := c.Get(key)
+
+		// THEN
+		require.True(t, ok)
+		assert.Equal(t, "diff output", string(value))
+	})
+
+	t.Run("misses keys that weren't stored", func(t *testing.T) {
+		// GIVEN
+		c, err := New(t.TempDir(), time.Hour, 0)
+		require.NoError(t, err)
+		require.NoError(t, c.Put(Key("module_a", "1.0.0", "1.1.0"), []byte("diff output")))
+
+		// WHEN
+		_, ok := c.Get(Key("module_a", "1.0.0", "1.2.0"))
+
+		// THEN
+		assert.False(t, ok)
+	})
+
+	t.Run("keys depend on the boundaries between parts", func(t *testing.T) {
+		// GIVEN
+		// WHEN
+		first := Key("module_a", "1.0.0")
+		second := Key("module_a1", ".0.0")
+
+		// THEN
+		assert.NotEqual(t, first, second)
+	})
+
+	t.Run("misses stale entries", func(t *testing.T) {
+		// GIVEN
+		dir := t.TempDir()
+		c, err := New(dir, time.Hour, 0)
+		require.NoError(t, err)
+		key := Key("module_a")
+		require.NoError(t, c.Put(key, []byte("diff output")))
+		setAge(t, filepath.Join(dir, key), 2*time.Hour)
+
+		// WHEN
+		_, ok := c.Get(key)
+
+		// THEN
+		assert.False(t, ok)
+	})
+
+	t.Run("eviction removes stale entries", func(t *testing.T) {
+		// GIVEN
+		dir := t.TempDir()
+		c, err := New(dir, time.Hour, 0)
+		require.NoError(t, err)
+		staleKey := Key("module_a")
+		freshKey := Key("module_b")
+		require.NoError(t, c.Put(staleKey, []byte("stale")))
+		require.NoError(t, c.Put(freshKey, []byte("fresh")))
+		setAge(t, filepath.Join(dir, staleKey), 2*time.Hour)
+
+		// WHEN
+		err = c.Evict()
+
+		// THEN
+		require.NoError(t, err)
+		assert.NoFileExists(t, filepath.Join(dir, staleKey))
+		assert.FileExists(t, filepath.Join(dir, freshKey))
+	})
+
+	t.Run("eviction removes the oldest entries when over the size limit", func(t *testing.T) {
+		// GIVEN
+		dir := t.TempDir()
+		c, err := New(dir, 0, 10)
+		require.NoError(t, err)
+		keys := []string{Key("module_a"), Key("module_b"), Key("module_c")}
+		for i, key := range keys {
+			require.NoError(t, c.Put(key, []byte("12345")))
+			setAge(t, filepath.Join(dir, key), time.Duration(len(keys)-i)*time.Minute)
+		}
+
+		// WHEN
+		err = c.Evict()
+
+		// THEN
+		require.NoError(t, err)
+		assert.NoFileExists(t, filepath.Join(dir, keys[0]))
+		assert.FileExists(t, filepath.Join(dir, keys[1]))
+		assert.FileExists(t, filepath.Join(dir, keys[2]))
+	})
+
+	t.Run("eviction leaves unrelated files alone", func(t *testing.T) {
+		// GIVEN
+		dir := t.TempDir()
+		c, err := New(dir, time.Hour, 1)
+		require.NoError(t, err)
+		otherPath := filepath.Join(dir, "notes.txt")
+		require.NoError(t, os.WriteFile(otherPath, []byte("keep me"), 0o644))
+		setAge(t, otherPath, 2*time.Hour)
+
+		// WHEN
+		err = c.Evict()
+
+		// THEN
+		require.NoError(t, err)
+		assert.FileExists(t, otherPath)
+	})
+
+	// FAILURES
+	t.Run("fails if the cache directory can't be created", func(t *testing.T) {
+		// GIVEN
+		filePath := filepath.Join(t.TempDir(), "file")
+		require.NoError(t, os.WriteFile(filePath, nil, 0o644))
+
+		// WHEN
+		_, err := New(filepath.Join(filePath, "cache"), time.Hour, 0)
+
+		// THEN
+		require.ErrorIs(t, err, ErrCouldntCreateCacheDir)
+	})
+}
+
+func setAge(t *testing.T, path string, age time.Duration) {
+	t.Helper()
+	modTime := time.Now().Add(-age)
+	require.NoError(t, os.Chtimes(path, modTime, modTime))
+}
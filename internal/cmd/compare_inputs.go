@@ -0,0 +1,209 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"runtime"
+	"time"
+
+	"github.com/dhth/tflens/internal/domain"
+	"github.com/dhth/tflens/internal/services"
+	"github.com/dhth/tflens/internal/view"
+	"github.com/spf13/cobra"
+)
+
+var errOutputFormatNotSupported = errors.New("output format not supported by this command")
+
+var compareInputsOutputFormats = []string{"stdout", "json", "html"}
+
+func newCompareInputsCmd() *cobra.Command {
+	var config domain.Config
+	var configPath string
+	var outputFmtStr string
+	var htmlTemplatePath string
+	var htmlOutputPath string
+	var htmlTitle string
+	var stdoutPlain bool
+	var runAll bool
+	var jobs int
+
+	cmd := &cobra.Command{
+		Use:   "compare-inputs [COMPARISON]...",
+		Short: "Compare the arguments passed to modules across multiple Terraform sources",
+		Long: `Compare the arguments passed to modules across multiple Terraform sources.
+
+This uses the comparisons configured for compare-modules. For every module
+present in two or more of a comparison's sources, it lists each argument set in
+the module's blocks, along with its expression in every source. Expressions are
+compared as written (after formatting them, and dropping comments), and are not
+evaluated.
+
+An argument's status is one of:
+- same:    the argument's expression is the same in every source
+- differs: the argument's expression differs between sources
+- missing: the argument isn't set in some of the sources the module is in
+
+Terragrunt sources are not supported; comparisons that have them are skipped
+when running all comparisons.
+
+$ tflens compare-inputs apps
+
+module      argument          dev       prod      status
+module_a    instance_count    1         3         differs
+            version           "1.2.0"   "1.2.0"   same
+module_b    multi_az          -         true      missing
+            version           "0.4.0"   "0.4.0"   same
+`,
+		Args: func(_ *cobra.Command, args []string) error {
+			if runAll && len(args) > 0 {
+				return errComparisonsWithAllFlag
+			}
+			if !runAll && len(args) == 0 {
+				return errNoComparisonsSpecified
+			}
+
+			return nil
+		},
+		SilenceUsage: true,
+
+		PreRunE: func(_ *cobra.Command, _ []string) error {
+			configBytes, err := os.ReadFile(configPath)
+			if err != nil {
+				return fmt.Errorf("%w: %w", ErrCouldntReadConfigFile, err)
+			}
+			config, err = domain.GetConfig(configBytes)
+			if err != nil {
+				return err
+			}
+
+			return nil
+		},
+		RunE: func(command *cobra.Command, args []string) error {
+			if jobs < 0 {
+				return fmt.Errorf("%w: %d", errInvalidJobs, jobs)
+			}
+			if jobs == 0 {
+				jobs = runtime.NumCPU()
+			}
+
+			outputFmt, outputFmtOk := domain.ParseOutputFormat(outputFmtStr)
+			if !outputFmtOk {
+				return fmt.Errorf("%w: %q; allowed values: %v", errInvalidOutputFormat, outputFmtStr, compareInputsOutputFormats)
+			}
+
+			comparisons, err := selectComparisons(config.CompareModules.Comparisons, args, runAll)
+			if err != nil {
+				return err
+			}
+
+			comparisons, err = dropTerragruntComparisons(comparisons, runAll, services.ErrInputsUnsupportedForTerragrunt)
+			if err != nil {
+				return err
+			}
+
+			results := make([]domain.InputsResult, 0, len(comparisons))
+			for _, comparison := range comparisons {
+				result, err := services.GetInputsResult(command.Context(), comparison, jobs)
+				if err != nil {
+					return err
+				}
+				results = append(results, result)
+			}
+
+			switch outputFmt {
+			case domain.StdoutOutput:
+				err := view.RenderInputsStdout(os.Stdout, results, stdoutPlain)
+				if err != nil {
+					return fmt.Errorf("failed to render stdout: %w", err)
+				}
+
+			case domain.JSONOutput:
+				err := view.RenderInputsJSON(os.Stdout, results)
+				if err != nil {
+					return fmt.Errorf("failed to render JSON: %w", err)
+				}
+
+			case domain.HtmlOutput:
+				output := resultsOutput{
+					htmlTemplatePath: htmlTemplatePath,
+					htmlOutputPath:   htmlOutputPath,
+					htmlTitle:        htmlTitle,
+				}
+				err := writeHTMLReport(output, func(htmlConfig view.HTMLConfig) (string, error) {
+					return view.RenderInputsHTML(results, htmlConfig, time.Now())
+				})
+				if err != nil {
+					return err
+				}
+
+			default:
+				return fmt.Errorf("%w: %q; allowed values: %v", errOutputFormatNotSupported, outputFmtStr, compareInputsOutputFormats)
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(
+		&configPath,
+		"config-path",
+		"c",
+		configFileName,
+		"path to tflens' configuration file",
+	)
+
+	cmd.Flags().BoolVarP(
+		&runAll,
+		"all",
+		"a",
+		false,
+		"run all configured comparisons",
+	)
+
+	cmd.Flags().IntVarP(
+		&jobs,
+		"jobs",
+		"j",
+		0,
+		"maximum number of sources to parse concurrently (0 means the number of CPUs)",
+	)
+
+	cmd.Flags().StringVarP(
+		&outputFmtStr,
+		"output-format",
+		"o",
+		"stdout",
+		fmt.Sprintf("output format for results; allowed values: %v", compareInputsOutputFormats),
+	)
+
+	cmd.Flags().StringVar(
+		&htmlTemplatePath,
+		"html-template",
+		"",
+		"path to a custom HTML template (optional)",
+	)
+
+	cmd.Flags().StringVar(
+		&htmlOutputPath,
+		"html-output",
+		"tflens-inputs-report.html",
+		"path where the HTML report should be written",
+	)
+
+	cmd.Flags().StringVar(
+		&htmlTitle,
+		"html-title",
+		"inputs",
+		"title for the HTML report",
+	)
+
+	cmd.Flags().BoolVar(
+		&stdoutPlain,
+		"stdout-plain",
+		false,
+		"do not use colors in stdout output",
+	)
+
+	return cmd
+}
@@ -5,8 +5,11 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"runtime"
+	"slices"
 	"time"
 
+	"github.com/dhth/tflens/internal/cache"
 	"github.com/dhth/tflens/internal/domain"
 	"github.com/dhth/tflens/internal/services"
 	"github.com/dhth/tflens/internal/view"
@@ -16,11 +19,20 @@ import (
 var (
 	errInvalidOutputFormat     = errors.New("invalid output format provided")
 	ErrModulesNotInSync        = errors.New("modules not in sync")
+	ErrModulesAheadOfUpstream  = errors.New("modules ahead of upstream")
+	ErrDiffsUnavailable        = errors.New("diffs unavailable for some modules")
 	errCouldntReadHTMLTemplate = errors.New("couldn't read HTML template")
 	errCouldntRenderHTML       = errors.New("couldn't render HTML")
 	errCouldntWriteHTMLReport  = errors.New("couldn't write HTML report")
 	errCouldntCreateOutputDir  = errors.New("couldn't create output directory")
 	ErrCouldntReadConfigFile   = errors.New("couldn't read config file")
+	errNoComparisonsSpecified  = errors.New("no comparison specified; provide comparison names or use --all")
+	errComparisonsWithAllFlag  = errors.New("comparison names cannot be provided along with --all")
+	errInvalidJobs             = errors.New("number of jobs cannot be negative")
+	errInvalidDiffTimeout      = errors.New("diff timeout cannot be negative")
+	errInvalidCacheMaxAge      = errors.New("cache max age cannot be negative")
+	errInvalidCacheMaxSize     = errors.New("cache max size cannot be negative")
+	errCouldntFindCacheDir     = errors.New("couldn't determine cache directory; provide one using --cache-dir, or use --no-cache")
 )
 
 func newCompareModulesCmd() *cobra.Command {
@@ -33,9 +45,17 @@ func newCompareModulesCmd() *cobra.Command {
 	var htmlOutputPath string
 	var htmlTitle string
 	var stdoutPlain bool
+	var runAll bool
+	var jobs int
+	var diffTimeout time.Duration
+	var continueOnDiffErrors bool
+	var noCache bool
+	var cacheDir string
+	var cacheMaxAge time.Duration
+	var cacheMaxSizeMB int64
 
 	cmd := &cobra.Command{
-		Use:   "compare-modules <COMPARISON>",
+		Use:   "compare-modules [COMPARISON]...",
 		Short: "Compare modules by an attribute across multiple Terraform sources",
 		Long: `Compare modules by an attribute across multiple Terraform sources.
 
@@ -50,9 +70,11 @@ compareModules:
   comparisons:
     # will be used when specifying the comparison to be run
     - name: apps
-      # the attribute to use for comparison
+      # the attribute to use for comparison; use attributeKeys instead to
+      # compare several attributes (eg. [version, source])
       attributeKey: source
-      # where to look for terraform files
+      # where to look for terraform files; a path can point to a file, a
+      # directory, or a glob pattern (eg. environments/dev/**/apps/*.tf)
       sources:
         - path: environments/dev/virginia/apps/main.tf
           # this label will appear in the comparison output
@@ -61,7 +83,8 @@ compareModules:
           label: prod-us
         - path: environments/prod/frankfurt/apps/main.tf
           # regex to extract the desired string from the attribute value
-          # only applies to this source, overrides the global valueRegex
+          # only applies to this source, overrides the comparison's and the
+          # global valueRegex
           # optional
           valueRegex: "v?(\\d+\\.\\d+\\.\\d+)"
           label: prod-eu
@@ -78,8 +101,23 @@ module      dev       prod-us    prod-eu    in-sync
 module_a    1.0.24    1.0.24     1.0.24     ✓
 module_b    0.2.0     0.2.0      -          ✗
 module_c    1.1.1     1.1.1      1.1.0      ✗
+
+Multiple comparisons can be run in one go, either by providing their names or
+by using --all.
+
+$ tflens compare-modules apps data
+$ tflens compare-modules --all
 `,
-		Args:         cobra.ExactArgs(1),
+		Args: func(_ *cobra.Command, args []string) error {
+			if runAll && len(args) > 0 {
+				return errComparisonsWithAllFlag
+			}
+			if !runAll && len(args) == 0 {
+				return errNoComparisonsSpecified
+			}
+
+			return nil
+		},
 		SilenceUsage: true,
 
 		PreRunE: func(_ *cobra.Command, _ []string) error {
@@ -94,84 +132,77 @@ module_c    1.1.1     1.1.1      1.1.0      ✗
 
 			return nil
 		},
-		RunE: func(_ *cobra.Command, args []string) error {
-			outputFmt, outputFmtOk := domain.ParseOutputFormat(outputFmtStr)
-			if !outputFmtOk {
-				return fmt.Errorf("%w: %q; allowed values: %v", errInvalidOutputFormat, outputFmtStr, domain.GetOutputFormatValues())
+		RunE: func(command *cobra.Command, args []string) error {
+			if jobs < 0 {
+				return fmt.Errorf("%w: %d", errInvalidJobs, jobs)
+			}
+			if jobs == 0 {
+				jobs = runtime.NumCPU()
 			}
 
-			comparisonName := args[0]
-			var comparisonToUse *domain.Comparison
-			for i := range config.CompareModules.Comparisons {
-				if config.CompareModules.Comparisons[i].Name == comparisonName {
-					comparisonToUse = &config.CompareModules.Comparisons[i]
-					break
-				}
+			if diffTimeout < 0 {
+				return fmt.Errorf("%w: %s", errInvalidDiffTimeout, diffTimeout)
 			}
 
-			if comparisonToUse == nil {
-				return fmt.Errorf("%w: %q", ErrComparisonNotFound, comparisonName)
+			if cacheMaxAge < 0 {
+				return fmt.Errorf("%w: %s", errInvalidCacheMaxAge, cacheMaxAge)
+			}
+			if cacheMaxSizeMB < 0 {
+				return fmt.Errorf("%w: %d", errInvalidCacheMaxSize, cacheMaxSizeMB)
+			}
+
+			outputFmt, outputFmtOk := domain.ParseOutputFormat(outputFmtStr)
+			if !outputFmtOk {
+				return fmt.Errorf("%w: %q; allowed values: %v", errInvalidOutputFormat, outputFmtStr, domain.GetOutputFormatValues())
 			}
 
-			result, err := services.GetComparisonResult(
-				*comparisonToUse,
-				config.CompareModules.ValueRegex,
-				ignoreMissingModules,
-				includeDiffs,
-			)
+			comparisons, err := selectComparisons(config.CompareModules.Comparisons, args, runAll)
 			if err != nil {
 				return err
 			}
 
-			switch outputFmt {
-			case domain.StdoutOutput:
-				err := view.RenderStdout(os.Stdout, result, stdoutPlain)
-				if err != nil {
-					return fmt.Errorf("failed to render stdout: %w", err)
-				}
-
-				for _, moduleRes := range result.Modules {
-					if moduleRes.Status == domain.StatusOutOfSync {
-						return ErrModulesNotInSync
-					}
-				}
-
-			case domain.HtmlOutput:
-				var customTemplate *string
-				if htmlTemplatePath != "" {
-					templateBytes, err := os.ReadFile(htmlTemplatePath)
-					if err != nil {
-						return fmt.Errorf("%w %q: %w", errCouldntReadHTMLTemplate, htmlTemplatePath, err)
-					}
-					templateStr := string(templateBytes)
-					customTemplate = &templateStr
-				}
-
-				htmlConfig := view.HTMLConfig{
-					CustomTemplate: customTemplate,
-					Title:          htmlTitle,
-				}
-
-				html, err := view.RenderHTML(result, htmlConfig, time.Now())
+			// the cache is only an optimisation; diffs are computed without it
+			// if it can't be set up
+			var diffCache *cache.Cache
+			if includeDiffs && !noCache {
+				diffCache, err = getDiffCache(cacheDir, cacheMaxAge, cacheMaxSizeMB)
 				if err != nil {
-					return fmt.Errorf("%w: %w", errCouldntRenderHTML, err)
+					printWarning(fmt.Errorf("%w; diffs won't be cached", err))
+					diffCache = nil
 				}
+			}
 
-				outputDir := filepath.Dir(htmlOutputPath)
-				err = os.MkdirAll(outputDir, 0o755)
+			results := make([]domain.ComparisonResult, 0, len(comparisons))
+			for _, comparison := range comparisons {
+				result, err := services.GetComparisonResult(command.Context(), comparison, services.ComparisonOptions{
+					GlobalValueRegex:     config.CompareModules.ValueRegex,
+					IgnoreMissingModules: ignoreMissingModules,
+					IncludeDiffs:         includeDiffs,
+					Jobs:                 jobs,
+					DiffTimeout:          diffTimeout,
+					ContinueOnDiffErrors: continueOnDiffErrors,
+					DiffCache:            diffCache,
+					Warn:                 printWarning,
+				})
 				if err != nil {
-					return fmt.Errorf("%w: %w", errCouldntCreateOutputDir, err)
+					return err
 				}
+				results = append(results, result)
+			}
 
-				err = os.WriteFile(htmlOutputPath, []byte(html), 0o644)
+			if diffCache != nil {
+				err := diffCache.Evict()
 				if err != nil {
-					return fmt.Errorf("%w: %w", errCouldntWriteHTMLReport, err)
+					printWarning(err)
 				}
-
-				fmt.Printf("HTML report written to %q\n", htmlOutputPath)
 			}
 
-			return nil
+			return renderComparisonResults(results, outputFmt, resultsOutput{
+				htmlTemplatePath: htmlTemplatePath,
+				htmlOutputPath:   htmlOutputPath,
+				htmlTitle:        htmlTitle,
+				stdoutPlain:      stdoutPlain,
+			})
 		},
 	}
 
@@ -183,6 +214,14 @@ module_c    1.1.1     1.1.1      1.1.0      ✗
 		"path to tflens' configuration file",
 	)
 
+	cmd.Flags().BoolVarP(
+		&runAll,
+		"all",
+		"a",
+		false,
+		"run all configured comparisons",
+	)
+
 	cmd.Flags().BoolVarP(
 		&ignoreMissingModules,
 		"ignore-missing-modules",
@@ -199,6 +238,56 @@ module_c    1.1.1     1.1.1      1.1.0      ✗
 		"include diffs between versions in report (requires diffConfig in tflens' config)",
 	)
 
+	cmd.Flags().DurationVar(
+		&diffTimeout,
+		"diff-timeout",
+		0,
+		"timeout for each diff command, eg. 30s; overrides the timeout in diffConfig",
+	)
+
+	cmd.Flags().BoolVar(
+		&continueOnDiffErrors,
+		"continue-on-diff-errors",
+		false,
+		"report a failing diff command as \"diff unavailable\" for its module instead of stopping",
+	)
+
+	cmd.Flags().BoolVar(
+		&noCache,
+		"no-cache",
+		false,
+		"do not read diffs from, or write them to, the diff cache",
+	)
+
+	cmd.Flags().StringVar(
+		&cacheDir,
+		"cache-dir",
+		"",
+		"directory to cache diffs in (defaults to \"tflens\" in the user's cache directory)",
+	)
+
+	cmd.Flags().DurationVar(
+		&cacheMaxAge,
+		"cache-max-age",
+		7*24*time.Hour,
+		"age after which cached diffs are discarded (0 means never)",
+	)
+
+	cmd.Flags().Int64Var(
+		&cacheMaxSizeMB,
+		"cache-max-size-mb",
+		100,
+		"size in MB beyond which the oldest cached diffs are discarded (0 means no limit)",
+	)
+
+	cmd.Flags().IntVarP(
+		&jobs,
+		"jobs",
+		"j",
+		0,
+		"maximum number of sources to parse, or diff commands to run, concurrently (0 means the number of CPUs)",
+	)
+
 	cmd.Flags().StringVarP(
 		&outputFmtStr,
 		"output-format",
@@ -237,3 +326,191 @@ module_c    1.1.1     1.1.1      1.1.0      ✗
 
 	return cmd
 }
+
+// resultsOutput holds the flags that control how comparison results are
+// rendered.
+type resultsOutput struct {
+	htmlTemplatePath string
+	htmlOutputPath   string
+	htmlTitle        string
+	stdoutPlain      bool
+}
+
+// renderComparisonResults renders results in the requested format, and
+// returns the error the command should exit with.
+func renderComparisonResults(results []domain.ComparisonResult, outputFmt domain.OutputFormat, output resultsOutput) error {
+	switch outputFmt {
+	case domain.StdoutOutput:
+		err := view.RenderStdout(os.Stdout, results, output.stdoutPlain)
+		if err != nil {
+			return fmt.Errorf("failed to render stdout: %w", err)
+		}
+
+		return resultsError(results)
+
+	case domain.JSONOutput:
+		err := view.RenderJSON(os.Stdout, results)
+		if err != nil {
+			return fmt.Errorf("failed to render JSON: %w", err)
+		}
+
+		return resultsError(results)
+
+	case domain.MarkdownOutput:
+		err := view.RenderMarkdown(os.Stdout, results)
+		if err != nil {
+			return fmt.Errorf("failed to render markdown: %w", err)
+		}
+
+		return resultsError(results)
+
+	case domain.JUnitOutput:
+		err := view.RenderJUnit(os.Stdout, results)
+		if err != nil {
+			return fmt.Errorf("failed to render JUnit XML: %w", err)
+		}
+
+		return resultsError(results)
+
+	case domain.HtmlOutput:
+		err := writeHTMLReport(output, func(htmlConfig view.HTMLConfig) (string, error) {
+			return view.RenderHTML(results, htmlConfig, time.Now())
+		})
+		if err != nil {
+			return err
+		}
+
+		return resultsError(results)
+	}
+
+	return nil
+}
+
+// writeHTMLReport renders an HTML report using the custom template in output,
+// if any, and writes it to output's HTML path.
+func writeHTMLReport(output resultsOutput, render func(view.HTMLConfig) (string, error)) error {
+	var customTemplate *string
+	if output.htmlTemplatePath != "" {
+		templateBytes, err := os.ReadFile(output.htmlTemplatePath)
+		if err != nil {
+			return fmt.Errorf("%w %q: %w", errCouldntReadHTMLTemplate, output.htmlTemplatePath, err)
+		}
+		templateStr := string(templateBytes)
+		customTemplate = &templateStr
+	}
+
+	htmlConfig := view.HTMLConfig{
+		CustomTemplate: customTemplate,
+		Title:          output.htmlTitle,
+	}
+
+	html, err := render(htmlConfig)
+	if err != nil {
+		return fmt.Errorf("%w: %w", errCouldntRenderHTML, err)
+	}
+
+	outputDir := filepath.Dir(output.htmlOutputPath)
+	err = os.MkdirAll(outputDir, 0o755)
+	if err != nil {
+		return fmt.Errorf("%w: %w", errCouldntCreateOutputDir, err)
+	}
+
+	err = os.WriteFile(output.htmlOutputPath, []byte(html), 0o644)
+	if err != nil {
+		return fmt.Errorf("%w: %w", errCouldntWriteHTMLReport, err)
+	}
+
+	fmt.Printf("HTML report written to %q\n", output.htmlOutputPath)
+
+	return nil
+}
+
+// resultsError returns the error that the command should exit with based on the
+// comparison results; promotion order violations take precedence over
+// ordinary drift. Unavailable diffs are reported alongside either.
+func resultsError(results []domain.ComparisonResult) error {
+	var errs []error
+
+	switch {
+	case slices.ContainsFunc(results, domain.ComparisonResult.HasModulesAheadOfUpstream):
+		errs = append(errs, ErrModulesAheadOfUpstream)
+	case slices.ContainsFunc(results, domain.ComparisonResult.HasFailingModules):
+		errs = append(errs, ErrModulesNotInSync)
+	}
+
+	if slices.ContainsFunc(results, domain.ComparisonResult.HasDiffErrors) {
+		errs = append(errs, ErrDiffsUnavailable)
+	}
+
+	return errors.Join(errs...)
+}
+
+// printWarning reports a problem that doesn't fail the command on stderr,
+// leaving stdout to the results.
+func printWarning(err error) {
+	fmt.Fprintf(os.Stderr, "warning: %s\n", err)
+}
+
+func getDiffCache(dir string, maxAge time.Duration, maxSizeMB int64) (*cache.Cache, error) {
+	if dir == "" {
+		userCacheDir, err := os.UserCacheDir()
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", errCouldntFindCacheDir, err)
+		}
+		dir = filepath.Join(userCacheDir, "tflens")
+	}
+
+	return cache.New(dir, maxAge, maxSizeMB*1024*1024)
+}
+
+func selectComparisons(comparisons []domain.Comparison, names []string, all bool) ([]domain.Comparison, error) {
+	if all {
+		return comparisons, nil
+	}
+
+	selected := make([]domain.Comparison, 0, len(names))
+	seen := make(map[string]struct{})
+	for _, name := range names {
+		if _, ok := seen[name]; ok {
+			continue
+		}
+		seen[name] = struct{}{}
+
+		index := slices.IndexFunc(comparisons, func(c domain.Comparison) bool {
+			return c.Name == name
+		})
+		if index == -1 {
+			return nil, fmt.Errorf("%w: %q", ErrComparisonNotFound, name)
+		}
+
+		selected = append(selected, comparisons[index])
+	}
+
+	return selected, nil
+}
+
+// dropTerragruntComparisons removes the comparisons that have terragrunt
+// sources, for commands that don't support them. When running all comparisons,
+// they are skipped with a note; comparisons that were asked for by name lead
+// to unsupportedErr instead, before any comparison is run.
+func dropTerragruntComparisons(comparisons []domain.Comparison, all bool, unsupportedErr error) ([]domain.Comparison, error) {
+	supported := make([]domain.Comparison, 0, len(comparisons))
+	for _, comparison := range comparisons {
+		index := slices.IndexFunc(comparison.Sources, func(s domain.Source) bool {
+			return s.Kind == domain.TerragruntSource
+		})
+		if index == -1 {
+			supported = append(supported, comparison)
+			continue
+		}
+
+		label := comparison.Sources[index].Label
+		if !all {
+			return nil, fmt.Errorf("%w: comparison %q has a terragrunt source (%q)", unsupportedErr, comparison.Name, label)
+		}
+
+		fmt.Fprintf(os.Stderr, "skipping comparison %q, as it has a terragrunt source (%q)\n", comparison.Name, label)
+	}
+
+	return supported, nil
+}
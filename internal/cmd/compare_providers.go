@@ -0,0 +1,183 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+
+	"github.com/dhth/tflens/internal/domain"
+	"github.com/dhth/tflens/internal/services"
+	"github.com/spf13/cobra"
+)
+
+func newCompareProvidersCmd() *cobra.Command {
+	var config domain.Config
+	var configPath string
+	var outputFmtStr string
+	var ignoreMissingProviders bool
+	var htmlTemplatePath string
+	var htmlOutputPath string
+	var htmlTitle string
+	var stdoutPlain bool
+	var runAll bool
+	var jobs int
+
+	cmd := &cobra.Command{
+		Use:   "compare-providers [COMPARISON]...",
+		Short: "Compare provider version constraints across multiple Terraform sources",
+		Long: `Compare provider version constraints across multiple Terraform sources.
+
+This uses the comparisons configured for compare-modules. It reads the
+required_providers blocks, and required_version, from the terraform blocks in a
+comparison's sources, and compares the version constraint of every provider
+(and of terraform itself, reported as "terraform") across them.
+
+Constraints are compared as written; valueRegex doesn't apply to them. Semver
+drift can only be classified for constraints that are exact versions.
+
+Results are reported, and the command exits, the same way as compare-modules.
+Terragrunt sources are not supported; comparisons that have them are skipped
+when running all comparisons.
+
+$ tflens compare-providers apps
+
+provider     dev         prod-us     prod-eu     in-sync
+aws          ~> 5.40     ~> 5.40     ~> 5.31     ✗
+random       3.6.0       3.6.0       3.6.0       ✓
+terraform    >= 1.6.0    >= 1.6.0    >= 1.6.0    ✓
+`,
+		Args: func(_ *cobra.Command, args []string) error {
+			if runAll && len(args) > 0 {
+				return errComparisonsWithAllFlag
+			}
+			if !runAll && len(args) == 0 {
+				return errNoComparisonsSpecified
+			}
+
+			return nil
+		},
+		SilenceUsage: true,
+
+		PreRunE: func(_ *cobra.Command, _ []string) error {
+			configBytes, err := os.ReadFile(configPath)
+			if err != nil {
+				return fmt.Errorf("%w: %w", ErrCouldntReadConfigFile, err)
+			}
+			config, err = domain.GetConfig(configBytes)
+			if err != nil {
+				return err
+			}
+
+			return nil
+		},
+		RunE: func(command *cobra.Command, args []string) error {
+			if jobs < 0 {
+				return fmt.Errorf("%w: %d", errInvalidJobs, jobs)
+			}
+			if jobs == 0 {
+				jobs = runtime.NumCPU()
+			}
+
+			outputFmt, outputFmtOk := domain.ParseOutputFormat(outputFmtStr)
+			if !outputFmtOk {
+				return fmt.Errorf("%w: %q; allowed values: %v", errInvalidOutputFormat, outputFmtStr, domain.GetOutputFormatValues())
+			}
+
+			comparisons, err := selectComparisons(config.CompareModules.Comparisons, args, runAll)
+			if err != nil {
+				return err
+			}
+
+			comparisons, err = dropTerragruntComparisons(comparisons, runAll, services.ErrProvidersUnsupportedForTerragrunt)
+			if err != nil {
+				return err
+			}
+
+			results := make([]domain.ComparisonResult, 0, len(comparisons))
+			for _, comparison := range comparisons {
+				result, err := services.GetProvidersResult(command.Context(), comparison, ignoreMissingProviders, jobs)
+				if err != nil {
+					return err
+				}
+				results = append(results, result)
+			}
+
+			return renderComparisonResults(results, outputFmt, resultsOutput{
+				htmlTemplatePath: htmlTemplatePath,
+				htmlOutputPath:   htmlOutputPath,
+				htmlTitle:        htmlTitle,
+				stdoutPlain:      stdoutPlain,
+			})
+		},
+	}
+
+	cmd.Flags().StringVarP(
+		&configPath,
+		"config-path",
+		"c",
+		configFileName,
+		"path to tflens' configuration file",
+	)
+
+	cmd.Flags().BoolVarP(
+		&runAll,
+		"all",
+		"a",
+		false,
+		"run all configured comparisons",
+	)
+
+	cmd.Flags().BoolVarP(
+		&ignoreMissingProviders,
+		"ignore-missing-providers",
+		"i",
+		false,
+		"to not have the absence of a provider lead to an out-of-sync status",
+	)
+
+	cmd.Flags().IntVarP(
+		&jobs,
+		"jobs",
+		"j",
+		0,
+		"maximum number of sources to parse concurrently (0 means the number of CPUs)",
+	)
+
+	cmd.Flags().StringVarP(
+		&outputFmtStr,
+		"output-format",
+		"o",
+		"stdout",
+		fmt.Sprintf("output format for results; allowed values: %v", domain.GetOutputFormatValues()),
+	)
+
+	cmd.Flags().StringVar(
+		&htmlTemplatePath,
+		"html-template",
+		"",
+		"path to a custom HTML template (optional)",
+	)
+
+	cmd.Flags().StringVar(
+		&htmlOutputPath,
+		"html-output",
+		"tflens-providers-report.html",
+		"path where the HTML report should be written",
+	)
+
+	cmd.Flags().StringVar(
+		&htmlTitle,
+		"html-title",
+		"providers",
+		"title for the HTML report",
+	)
+
+	cmd.Flags().BoolVar(
+		&stdoutPlain,
+		"stdout-plain",
+		false,
+		"do not use colors in stdout output",
+	)
+
+	return cmd
+}
@@ -3,9 +3,11 @@ package cmd
 import (
 	"errors"
 
+	"github.com/dhth/tflens/internal/services"
 	"github.com/dhth/tflens/internal/view"
 )
 
 func IsErrorUnexpected(err error) bool {
-	return errors.Is(err, view.ErrCouldntParseBuiltInTemplate)
+	return errors.Is(err, view.ErrCouldntParseBuiltInTemplate) ||
+		errors.Is(err, services.ErrUnexpectedSyncState)
 }
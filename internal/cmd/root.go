@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"context"
 	"errors"
 
 	"github.com/spf13/cobra"
@@ -12,13 +13,13 @@ const (
 
 var ErrComparisonNotFound = errors.New("comparison not found")
 
-func Execute(version string) error {
+func Execute(ctx context.Context, version string) error {
 	rootCmd, err := NewRootCommand(version)
 	if err != nil {
 		return err
 	}
 
-	return rootCmd.Execute()
+	return rootCmd.ExecuteContext(ctx)
 }
 
 func NewRootCommand(version string) (*cobra.Command, error) {
@@ -30,9 +31,15 @@ func NewRootCommand(version string) (*cobra.Command, error) {
 	}
 
 	compareModulesCmd := newCompareModulesCmd()
+	compareInputsCmd := newCompareInputsCmd()
+	compareProvidersCmd := newCompareProvidersCmd()
+	syncCmd := newSyncCmd()
 	configCmd := newConfigCmd()
 
 	rootCmd.AddCommand(compareModulesCmd)
+	rootCmd.AddCommand(compareInputsCmd)
+	rootCmd.AddCommand(compareProvidersCmd)
+	rootCmd.AddCommand(syncCmd)
 	rootCmd.AddCommand(configCmd)
 
 	rootCmd.CompletionOptions.DisableDefaultCmd = true
@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/dhth/tflens/internal/domain"
+	"github.com/dhth/tflens/internal/services"
+	"github.com/dhth/tflens/internal/view"
+	"github.com/spf13/cobra"
+)
+
+func newSyncCmd() *cobra.Command {
+	var config domain.Config
+	var configPath string
+	var fromLabel string
+	var toLabel string
+	var moduleNames []string
+	var dryRun bool
+
+	cmd := &cobra.Command{
+		Use:   "sync COMPARISON",
+		Short: "Bring modules in one source in sync with another",
+		Long: `Bring modules in one source in sync with another.
+
+This rewrites the attribute configured for a comparison (like 'source' or
+'version') in the source labelled by --to, so that it matches the value in the
+source labelled by --from. When a valueRegex applies, only the extracted portion
+of the attribute is replaced. Formatting and comments are preserved.
+
+All out-of-sync modules present in both sources are synced, unless specific
+modules are requested via --module.
+
+$ tflens sync apps --from dev --to prod-us --dry-run
+$ tflens sync apps --from dev --to prod-us --module module_a --module module_b
+`,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+
+		PreRunE: func(_ *cobra.Command, _ []string) error {
+			configBytes, err := os.ReadFile(configPath)
+			if err != nil {
+				return fmt.Errorf("%w: %w", ErrCouldntReadConfigFile, err)
+			}
+			config, err = domain.GetConfig(configBytes)
+			if err != nil {
+				return err
+			}
+
+			return nil
+		},
+		RunE: func(command *cobra.Command, args []string) error {
+			comparisons, err := selectComparisons(config.CompareModules.Comparisons, args, false)
+			if err != nil {
+				return err
+			}
+
+			plan, err := services.PlanSync(
+				command.Context(),
+				comparisons[0],
+				config.CompareModules.ValueRegex,
+				fromLabel,
+				toLabel,
+				moduleNames,
+			)
+			if err != nil {
+				return err
+			}
+
+			if !dryRun {
+				err = services.ApplySyncPlan(plan)
+				if err != nil {
+					return err
+				}
+			}
+
+			return view.RenderSyncPlan(os.Stdout, plan, dryRun)
+		},
+	}
+
+	cmd.Flags().StringVarP(
+		&configPath,
+		"config-path",
+		"c",
+		configFileName,
+		"path to tflens' configuration file",
+	)
+
+	cmd.Flags().StringVar(
+		&fromLabel,
+		"from",
+		"",
+		"label of the source to take values from",
+	)
+
+	cmd.Flags().StringVar(
+		&toLabel,
+		"to",
+		"",
+		"label of the source to rewrite",
+	)
+
+	cmd.Flags().StringArrayVarP(
+		&moduleNames,
+		"module",
+		"m",
+		nil,
+		"module to sync; can be repeated (defaults to all out-of-sync modules)",
+	)
+
+	cmd.Flags().BoolVar(
+		&dryRun,
+		"dry-run",
+		false,
+		"print a diff of the changes instead of writing them",
+	)
+
+	_ = cmd.MarkFlagRequired("from")
+	_ = cmd.MarkFlagRequired("to")
+
+	return cmd
+}
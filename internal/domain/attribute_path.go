@@ -0,0 +1,103 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var ErrInvalidAttributePath = errors.New("invalid attribute path")
+
+// AttributePath points to an attribute of a block, or to a value nested in one
+// (eg. tags.team, node_pools[0].size, or tags["cost-center"]).
+type AttributePath struct {
+	// the block's attribute
+	Name string
+	// keys of objects and maps, or indexes of lists and tuples, in order
+	Steps []string
+}
+
+func ParseAttributePath(path string) (AttributePath, error) {
+	var zero AttributePath
+
+	name, rest := path, ""
+	if i := strings.IndexAny(path, ".["); i >= 0 {
+		name, rest = path[:i], path[i:]
+	}
+	if !isPathIdentifier(name) {
+		return zero, fmt.Errorf("%w %q: %q is not a valid attribute name", ErrInvalidAttributePath, path, name)
+	}
+
+	var steps []string
+	for rest != "" {
+		var step string
+		switch rest[0] {
+		case '.':
+			rest = rest[1:]
+			end := strings.IndexAny(rest, ".[")
+			if end < 0 {
+				end = len(rest)
+			}
+			step, rest = rest[:end], rest[end:]
+			if !isPathIdentifier(step) {
+				return zero, fmt.Errorf("%w %q: %q is not a valid key", ErrInvalidAttributePath, path, step)
+			}
+		case '[':
+			end := strings.IndexByte(rest, ']')
+			if end < 0 {
+				return zero, fmt.Errorf("%w %q: unclosed \"[\"", ErrInvalidAttributePath, path)
+			}
+			inner := rest[1:end]
+			rest = rest[end+1:]
+
+			if strings.HasPrefix(inner, `"`) {
+				unquoted, err := strconv.Unquote(inner)
+				if err != nil {
+					return zero, fmt.Errorf("%w %q: %s is not a valid quoted key", ErrInvalidAttributePath, path, inner)
+				}
+				step = unquoted
+			} else {
+				_, err := strconv.Atoi(inner)
+				if err != nil {
+					return zero, fmt.Errorf("%w %q: %q is neither an index nor a quoted key", ErrInvalidAttributePath, path, inner)
+				}
+				step = inner
+			}
+		default:
+			return zero, fmt.Errorf("%w %q: unexpected %q", ErrInvalidAttributePath, path, rest[0])
+		}
+
+		steps = append(steps, step)
+	}
+
+	return AttributePath{Name: name, Steps: steps}, nil
+}
+
+func (p AttributePath) IsNested() bool {
+	return len(p.Steps) > 0
+}
+
+// KeyStep returns the part of a path that refers to a key of an object or map,
+// in the form ParseAttributePath accepts.
+func KeyStep(key string) string {
+	if isPathIdentifier(key) {
+		return "." + key
+	}
+
+	return "[" + strconv.Quote(key) + "]"
+}
+
+func isPathIdentifier(s string) bool {
+	if s == "" {
+		return false
+	}
+
+	for _, r := range s {
+		if r != '_' && r != '-' && (r < 'a' || r > 'z') && (r < 'A' || r > 'Z') && (r < '0' || r > '9') {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,74 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAttributePath(t *testing.T) {
+	//-------------//
+	//  SUCCESSES  //
+	//-------------//
+
+	t.Run("parsing valid paths works", func(t *testing.T) {
+		// GIVEN
+		cases := map[string]AttributePath{
+			"source":                  {Name: "source"},
+			"tags.team":               {Name: "tags", Steps: []string{"team"}},
+			"node_pools[0].size":      {Name: "node_pools", Steps: []string{"0", "size"}},
+			`tags["cost-center"]`:     {Name: "tags", Steps: []string{"cost-center"}},
+			`tags["owner.team"].name`: {Name: "tags", Steps: []string{"owner.team", "name"}},
+		}
+
+		for input, expected := range cases {
+			// WHEN
+			got, err := ParseAttributePath(input)
+
+			// THEN
+			require.NoError(t, err, "input: %q", input)
+			assert.Equal(t, expected, got, "input: %q", input)
+		}
+	})
+
+	t.Run("key steps can be parsed back", func(t *testing.T) {
+		// GIVEN
+		keys := []string{"team", "cost-center", "owner.team", `say "hi"`}
+
+		for _, key := range keys {
+			// WHEN
+			got, err := ParseAttributePath("tags" + KeyStep(key))
+
+			// THEN
+			require.NoError(t, err, "key: %q", key)
+			assert.Equal(t, []string{key}, got.Steps, "key: %q", key)
+		}
+	})
+
+	//------------//
+	//  FAILURES  //
+	//------------//
+
+	t.Run("parsing invalid paths fails", func(t *testing.T) {
+		// GIVEN
+		cases := []string{
+			"",
+			".tags",
+			"tags.",
+			"tags..team",
+			"tags[0",
+			"tags[team]",
+			`tags["team]`,
+			"tags[0]team",
+		}
+
+		for _, input := range cases {
+			// WHEN
+			_, err := ParseAttributePath(input)
+
+			// THEN
+			assert.ErrorIs(t, err, ErrInvalidAttributePath, "input: %q", input)
+		}
+	})
+}
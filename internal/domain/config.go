@@ -4,6 +4,9 @@ import (
 	"fmt"
 	"regexp"
 	"strings"
+	"time"
+
+	"github.com/goccy/go-yaml"
 )
 
 type Config struct {
@@ -17,23 +20,105 @@ type CompareModules struct {
 }
 
 type Comparison struct {
-	Name          string
-	AttributeKey  string
+	Name string
+	// attributes compared for every module; the first one is the primary
+	// attribute, whose values are the ones diffs are generated for, and the
+	// ones that get synced
+	AttributeKeys []string
 	Sources       []Source
 	IgnoreModules []string
 	ValueRegex    *regexp.Regexp
-	DiffCfg       *DiffConfig
+	// one per pair of labels to generate diffs for
+	DiffCfgs  []DiffConfig
+	SemverCfg *SemverConfig
+	// labels, ordered from the most upstream to the most downstream one
+	PromotionOrder []string
+}
+
+type SemverConfig struct {
+	FailOn Drift
 }
 
 type Source struct {
-	Path  string
-	Label string
+	Path       string
+	Label      string
+	Kind       SourceKind
+	ValueRegex *regexp.Regexp
+	// git ref to read the source from; the working tree is used when empty
+	Ref string
+	// .tfvars files that set values for the source's variables; later files
+	// take precedence over earlier ones (only for terraform sources)
+	VarFiles []string `yaml:"varFiles,omitempty"`
+}
+
+type SourceKind uint8
+
+const (
+	TerraformSource SourceKind = iota
+	TerragruntSource
+)
+
+func ParseSourceKind(value string) (SourceKind, bool) {
+	switch value {
+	case "", "terraform":
+		return TerraformSource, true
+	case "terragrunt":
+		return TerragruntSource, true
+	default:
+		return TerraformSource, false
+	}
+}
+
+func GetSourceKindValues() []string {
+	return []string{"terraform", "terragrunt"}
 }
 
 type DiffConfig struct {
 	BaseLabel string
 	HeadLabel string
 	Cmd       []string
+	// optional command that prints the commits between two refs, one per line
+	LogCmd []string `yaml:"logCmd,omitempty"`
+	// used instead of Cmd (and LogCmd), when set
+	Git *GitDiffConfig `yaml:"git,omitempty"`
+	// zero means no timeout
+	Timeout time.Duration
+	// whether Cmd prints pre-rendered HTML, which is to be included in HTML
+	// reports as is, rather than escaped and highlighted
+	OutputIsHTML bool `yaml:"outputIsHTML,omitempty"`
+}
+
+// GitDiffConfig configures the built-in git diff provider. Its templates can
+// contain the placeholders {{module}} and {{value}}, which are replaced by a
+// module's name and by its value for a label respectively.
+type GitDiffConfig struct {
+	RepoPath     string
+	RefTemplate  string
+	PathTemplate string
+	// whether to collect the commits between refs as well
+	Log bool
+}
+
+const (
+	moduleDiffPlaceholder = "{{module}}"
+	valueDiffPlaceholder  = "{{value}}"
+)
+
+var diffPlaceholderRegex = regexp.MustCompile(`{{([^}]*)}}`)
+
+func (c GitDiffConfig) Ref(moduleName, value string) string {
+	return expandDiffTemplate(c.RefTemplate, moduleName, value)
+}
+
+func (c GitDiffConfig) Path(moduleName, value string) string {
+	return expandDiffTemplate(c.PathTemplate, moduleName, value)
+}
+
+func expandDiffTemplate(template, moduleName, value string) string {
+	return strings.NewReplacer(
+		moduleDiffPlaceholder, moduleName,
+		valueDiffPlaceholder, value,
+	).Replace(template)
 }
 
 type OutputFormat uint8
@@ -41,6 +126,9 @@ type OutputFormat uint8
 const (
 	StdoutOutput OutputFormat = iota
 	HtmlOutput
+	JSONOutput
+	MarkdownOutput
+	JUnitOutput
 )
 
 func ParseOutputFormat(value string) (OutputFormat, bool) {
@@ -49,13 +137,19 @@ func ParseOutputFormat(value string) (OutputFormat, bool) {
 		return StdoutOutput, true
 	case "html":
 		return HtmlOutput, true
+	case "json":
+		return JSONOutput, true
+	case "markdown":
+		return MarkdownOutput, true
+	case "junit":
+		return JUnitOutput, true
 	default:
 		return StdoutOutput, false
 	}
 }
 
 func GetOutputFormatValues() []string {
-	return []string{"stdout", "html"}
+	return []string{"stdout", "html", "json", "markdown", "junit"}
 }
 
 type rawConfig struct {
@@ -69,23 +163,118 @@ type rawCompareModules struct {
 }
 
 type rawComparison struct {
-	Name          string
-	AttributeKey  string         `yaml:"attributeKey"`
-	Sources       []rawSource    `yaml:"sources"`
-	IgnoreModules []string       `yaml:"ignoreModules,omitempty"`
-	ValueRegex    string         `yaml:"valueRegex,omitempty"`
-	DiffCfg       *rawDiffConfig `yaml:"diffConfig"`
+	Name           string
+	AttributeKey   string           `yaml:"attributeKey,omitempty"`
+	AttributeKeys  []string         `yaml:"attributeKeys,omitempty"`
+	Sources        []rawSource      `yaml:"sources"`
+	IgnoreModules  []string         `yaml:"ignoreModules,omitempty"`
+	ValueRegex     string           `yaml:"valueRegex,omitempty"`
+	DiffCfgs       *rawDiffConfigs  `yaml:"diffConfig"`
+	SemverCfg      *rawSemverConfig `yaml:"semver,omitempty"`
+	PromotionOrder []string         `yaml:"promotionOrder,omitempty"`
+}
+
+type rawSemverConfig struct {
+	FailOn string `yaml:"failOn,omitempty"`
 }
 
 type rawSource struct {
-	Path  string
-	Label string
+	Path       string
+	Label      string
+	Kind       string   `yaml:"kind,omitempty"`
+	ValueRegex string   `yaml:"valueRegex,omitempty"`
+	Ref        string   `yaml:"ref,omitempty"`
+	VarFiles   []string `yaml:"varFiles,omitempty"`
+}
+
+// rawDiffConfigs holds either a single diff config, or a list of them
+type rawDiffConfigs struct {
+	configs []rawDiffConfig
+	isList  bool
+}
+
+func (c *rawDiffConfigs) UnmarshalYAML(data []byte) error {
+	var value any
+	err := yaml.Unmarshal(data, &value)
+	if err != nil {
+		return err
+	}
+
+	if _, ok := value.([]any); ok {
+		c.isList = true
+		return yaml.Unmarshal(data, &c.configs)
+	}
+
+	var config rawDiffConfig
+	err = yaml.Unmarshal(data, &config)
+	if err != nil {
+		return err
+	}
+	c.configs = []rawDiffConfig{config}
+
+	return nil
+}
+
+func (c rawDiffConfigs) parse(labels map[string]struct{}) ([]DiffConfig, []string) {
+	var errors []string
+	diffCfgs := make([]DiffConfig, 0, len(c.configs))
+
+	if c.isList && len(c.configs) == 0 {
+		return nil, []string{"diffConfig needs to have at least one entry"}
+	}
+
+	//        base  head   diffConfig #
+	seenPairs := make(map[[2]string]int)
+	for i, rawCfg := range c.configs {
+		heading := "diffConfig has errors"
+		if c.isList {
+			heading = fmt.Sprintf("diffConfig #%d has errors", i+1)
+		}
+
+		diffCfg, diffErrors := rawCfg.parse(labels)
+		if len(diffErrors) == 0 {
+			pair := [2]string{diffCfg.BaseLabel, diffCfg.HeadLabel}
+			if previous, ok := seenPairs[pair]; ok {
+				diffErrors = append(diffErrors, fmt.Sprintf("labels %s..%s are the same as for diffConfig #%d", pair[0], pair[1], previous))
+			} else {
+				seenPairs[pair] = i + 1
+			}
+		}
+
+		if len(diffErrors) > 0 {
+			diffErrorStrs := make([]string, 0, len(diffErrors))
+			for _, err := range diffErrors {
+				diffErrorStrs = append(diffErrorStrs, fmt.Sprintf("    - %s", err))
+			}
+			errors = append(errors, fmt.Sprintf("%s:\n%s", heading, strings.Join(diffErrorStrs, "\n")))
+			continue
+		}
+
+		diffCfgs = append(diffCfgs, diffCfg)
+	}
+
+	if len(errors) > 0 {
+		return nil, errors
+	}
+
+	return diffCfgs, nil
 }
 
 type rawDiffConfig struct {
-	BaseLabel string   `yaml:"baseLabel"`
-	HeadLabel string   `yaml:"headLabel"`
-	Cmd       []string `yaml:"cmd"`
+	BaseLabel    string            `yaml:"baseLabel"`
+	HeadLabel    string            `yaml:"headLabel"`
+	Cmd          []string          `yaml:"cmd,omitempty"`
+	LogCmd       []string          `yaml:"logCmd,omitempty"`
+	Git          *rawGitDiffConfig `yaml:"git,omitempty"`
+	Timeout      string            `yaml:"timeout,omitempty"`
+	OutputIsHTML bool              `yaml:"outputIsHTML,omitempty"`
+}
+
+type rawGitDiffConfig struct {
+	RepoPath     string `yaml:"repoPath"`
+	RefTemplate  string `yaml:"refTemplate"`
+	PathTemplate string `yaml:"pathTemplate,omitempty"`
+	Log          bool   `yaml:"log,omitempty"`
 }
 
 func (c rawDiffConfig) parse(labels map[string]struct{}) (DiffConfig, []string) {
@@ -111,29 +300,146 @@ func (c rawDiffConfig) parse(labels map[string]struct{}) (DiffConfig, []string)
 		}
 	}
 
-	if len(c.Cmd) == 0 {
+	var trimmedCmd []string
+	var gitCfg *GitDiffConfig
+	switch {
+	case c.Git != nil && len(c.Cmd) > 0:
+		errors = append(errors, "only one of cmd and git can be set")
+	case c.Git != nil:
+		parsedGitCfg, gitErrors := c.Git.parse()
+		for _, err := range gitErrors {
+			errors = append(errors, fmt.Sprintf("git: %s", err))
+		}
+		gitCfg = &parsedGitCfg
+	case len(c.Cmd) == 0:
 		errors = append(errors, "cmd is empty")
+	default:
+		var cmdErrors []string
+		trimmedCmd, cmdErrors = trimCmd("cmd", c.Cmd)
+		errors = append(errors, cmdErrors...)
+	}
+
+	var trimmedLogCmd []string
+	if len(c.LogCmd) > 0 {
+		if c.Git != nil {
+			errors = append(errors, "logCmd cannot be used with git; use git.log instead")
+		} else {
+			var logCmdErrors []string
+			trimmedLogCmd, logCmdErrors = trimCmd("logCmd", c.LogCmd)
+			errors = append(errors, logCmdErrors...)
+		}
+	}
+
+	if c.OutputIsHTML && c.Git != nil {
+		errors = append(errors, "outputIsHTML cannot be used with git")
+	}
+
+	var timeout time.Duration
+	if timeoutStr := strings.TrimSpace(c.Timeout); len(timeoutStr) > 0 {
+		var err error
+		timeout, err = time.ParseDuration(timeoutStr)
+		if err != nil {
+			errors = append(errors, fmt.Sprintf("timeout %q is invalid; use a duration like 30s or 2m", timeoutStr))
+		} else if timeout <= 0 {
+			errors = append(errors, fmt.Sprintf("timeout %q needs to be positive", timeoutStr))
+		}
 	}
 
-	trimmedCmd := make([]string, 0, len(c.Cmd))
-	for i, cmdElement := range c.Cmd {
+	if len(errors) > 0 {
+		var zero DiffConfig
+		return zero, errors
+	}
+
+	return DiffConfig{
+		BaseLabel:    baseLabel,
+		HeadLabel:    headLabel,
+		Cmd:          trimmedCmd,
+		LogCmd:       trimmedLogCmd,
+		Git:          gitCfg,
+		Timeout:      timeout,
+		OutputIsHTML: c.OutputIsHTML,
+	}, nil
+}
+
+func trimCmd(key string, cmd []string) ([]string, []string) {
+	var errors []string
+
+	trimmedCmd := make([]string, 0, len(cmd))
+	for i, cmdElement := range cmd {
 		trimmedElement := strings.TrimSpace(cmdElement)
 		if len(trimmedElement) == 0 {
-			errors = append(errors, fmt.Sprintf("cmd[%d] is empty", i+1))
+			errors = append(errors, fmt.Sprintf("%s[%d] is empty", key, i+1))
 			continue
 		}
 
 		trimmedCmd = append(trimmedCmd, trimmedElement)
 	}
 
+	return trimmedCmd, errors
+}
+
+func (c rawGitDiffConfig) parse() (GitDiffConfig, []string) {
+	var errors []string
+
+	repoPath := strings.TrimSpace(c.RepoPath)
+	if len(repoPath) == 0 {
+		errors = append(errors, "repoPath is empty")
+	}
+
+	refTemplate := strings.TrimSpace(c.RefTemplate)
+	if len(refTemplate) == 0 {
+		errors = append(errors, "refTemplate is empty")
+	} else {
+		if !strings.Contains(refTemplate, valueDiffPlaceholder) {
+			errors = append(errors, fmt.Sprintf("refTemplate %q needs to contain %s", refTemplate, valueDiffPlaceholder))
+		}
+		if strings.HasPrefix(refTemplate, "-") {
+			errors = append(errors, fmt.Sprintf("refTemplate %q cannot start with \"-\"", refTemplate))
+		}
+		errors = append(errors, unknownDiffPlaceholderErrors("refTemplate", refTemplate)...)
+	}
+
+	pathTemplate := strings.TrimSpace(c.PathTemplate)
+	errors = append(errors, unknownDiffPlaceholderErrors("pathTemplate", pathTemplate)...)
+
 	if len(errors) > 0 {
-		var zero DiffConfig
-		return zero, errors
+		return GitDiffConfig{}, errors
 	}
 
-	return DiffConfig{
-		BaseLabel: baseLabel,
-		HeadLabel: headLabel,
-		Cmd:       trimmedCmd,
+	return GitDiffConfig{
+		RepoPath:     repoPath,
+		RefTemplate:  refTemplate,
+		PathTemplate: pathTemplate,
+		Log:          c.Log,
 	}, nil
 }
+
+func unknownDiffPlaceholderErrors(key, template string) []string {
+	var errors []string
+	for _, match := range diffPlaceholderRegex.FindAllStringSubmatch(template, -1) {
+		switch "{{" + match[1] + "}}" {
+		case moduleDiffPlaceholder, valueDiffPlaceholder:
+		default:
+			errors = append(errors, fmt.Sprintf("%s %q has an unknown placeholder %s; allowed placeholders: %s, %s",
+				key, template, match[0], moduleDiffPlaceholder, valueDiffPlaceholder))
+		}
+	}
+
+	return errors
+}
+
+func (c rawSemverConfig) parse() (SemverConfig, []string) {
+	failOnStr := strings.TrimSpace(c.FailOn)
+	if len(failOnStr) == 0 {
+		return SemverConfig{FailOn: DriftPrerelease}, nil
+	}
+
+	failOn, ok := ParseDrift(failOnStr)
+	if !ok {
+		return SemverConfig{}, []string{
+			fmt.Sprintf("failOn has an invalid value %q; allowed values: %v", failOnStr, GetDriftValues()),
+		}
+	}
+
+	return SemverConfig{FailOn: failOn}, nil
+}
@@ -2,8 +2,10 @@ package domain
 
 import (
 	"testing"
+	"time"
 
 	"github.com/gkampitakis/go-snaps/snaps"
+	"github.com/goccy/go-yaml"
 	"github.com/stretchr/testify/require"
 )
 
@@ -48,6 +50,61 @@ func TestRawDiffConfigParse(t *testing.T) {
 		snaps.MatchYAML(t, result)
 	})
 
+	t.Run("parsing config with a timeout works", func(t *testing.T) {
+		// GIVEN
+		rawCfg := rawDiffConfig{
+			BaseLabel: "base",
+			HeadLabel: "head",
+			Cmd:       []string{"./scripts/generate-diff.sh", "apps"},
+			Timeout:   " 1m30s ",
+		}
+
+		// WHEN
+		result, errors := rawCfg.parse(sourceLabels)
+
+		// THEN
+		require.Empty(t, errors)
+		require.Equal(t, 90*time.Second, result.Timeout)
+	})
+
+	t.Run("parsing config with a log command works", func(t *testing.T) {
+		// GIVEN
+		rawCfg := rawDiffConfig{
+			BaseLabel: "base",
+			HeadLabel: "head",
+			Cmd:       []string{"./scripts/generate-diff.sh", "apps"},
+			LogCmd:    []string{"./scripts/generate-log.sh ", " apps"},
+		}
+
+		// WHEN
+		result, errors := rawCfg.parse(sourceLabels)
+
+		// THEN
+		require.Empty(t, errors)
+		require.Equal(t, []string{"./scripts/generate-log.sh", "apps"}, result.LogCmd)
+	})
+
+	t.Run("parsing config with a git provider works", func(t *testing.T) {
+		// GIVEN
+		rawCfg := rawDiffConfig{
+			BaseLabel: "base",
+			HeadLabel: "head",
+			Git: &rawGitDiffConfig{
+				RepoPath:     " ../infrastructure ",
+				RefTemplate:  "{{module}}-v{{value}}",
+				PathTemplate: "modules/{{module}}",
+				Log:          true,
+			},
+		}
+
+		// WHEN
+		result, errors := rawCfg.parse(sourceLabels)
+
+		// THEN
+		require.Empty(t, errors)
+		snaps.MatchYAML(t, result)
+	})
+
 	//------------//
 	//  FAILURES  //
 	//------------//
@@ -99,4 +156,334 @@ func TestRawDiffConfigParse(t *testing.T) {
 		require.NotEmpty(t, errors)
 		snaps.MatchYAML(t, errors)
 	})
+
+	t.Run("parsing config with an invalid timeout fails", func(t *testing.T) {
+		// GIVEN
+		rawCfg := rawDiffConfig{
+			BaseLabel: "base",
+			HeadLabel: "head",
+			Cmd:       []string{"./scripts/generate-diff.sh", "apps"},
+			Timeout:   "soon",
+		}
+
+		// WHEN
+		_, errors := rawCfg.parse(sourceLabels)
+
+		// THEN
+		require.NotEmpty(t, errors)
+		snaps.MatchYAML(t, errors)
+	})
+
+	t.Run("parsing config with a non-positive timeout fails", func(t *testing.T) {
+		// GIVEN
+		rawCfg := rawDiffConfig{
+			BaseLabel: "base",
+			HeadLabel: "head",
+			Cmd:       []string{"./scripts/generate-diff.sh", "apps"},
+			Timeout:   "-5s",
+		}
+
+		// WHEN
+		_, errors := rawCfg.parse(sourceLabels)
+
+		// THEN
+		require.NotEmpty(t, errors)
+		snaps.MatchYAML(t, errors)
+	})
+
+	t.Run("setting both cmd and git fails", func(t *testing.T) {
+		// GIVEN
+		rawCfg := rawDiffConfig{
+			BaseLabel: "base",
+			HeadLabel: "head",
+			Cmd:       []string{"./scripts/generate-diff.sh", "apps"},
+			Git: &rawGitDiffConfig{
+				RepoPath:    "../infrastructure",
+				RefTemplate: "{{module}}-v{{value}}",
+			},
+		}
+
+		// WHEN
+		_, errors := rawCfg.parse(sourceLabels)
+
+		// THEN
+		require.NotEmpty(t, errors)
+		snaps.MatchYAML(t, errors)
+	})
+
+	t.Run("using logCmd with git fails", func(t *testing.T) {
+		// GIVEN
+		rawCfg := rawDiffConfig{
+			BaseLabel: "base",
+			HeadLabel: "head",
+			LogCmd:    []string{"./scripts/generate-log.sh", ""},
+			Git: &rawGitDiffConfig{
+				RepoPath:    "../infrastructure",
+				RefTemplate: "{{module}}-v{{value}}",
+			},
+		}
+
+		// WHEN
+		_, errors := rawCfg.parse(sourceLabels)
+
+		// THEN
+		require.NotEmpty(t, errors)
+		snaps.MatchYAML(t, errors)
+	})
+
+	t.Run("using outputIsHTML with git fails", func(t *testing.T) {
+		// GIVEN
+		rawCfg := rawDiffConfig{
+			BaseLabel: "base",
+			HeadLabel: "head",
+			Git: &rawGitDiffConfig{
+				RepoPath:    "../infrastructure",
+				RefTemplate: "{{module}}-v{{value}}",
+			},
+			OutputIsHTML: true,
+		}
+
+		// WHEN
+		_, errors := rawCfg.parse(sourceLabels)
+
+		// THEN
+		require.NotEmpty(t, errors)
+		snaps.MatchYAML(t, errors)
+	})
+
+	t.Run("parsing invalid git provider config fails", func(t *testing.T) {
+		// GIVEN
+		rawCfg := rawDiffConfig{
+			BaseLabel: "base",
+			HeadLabel: "head",
+			Git: &rawGitDiffConfig{
+				RepoPath:     " ",
+				RefTemplate:  "-{{module}}-{{version}}",
+				PathTemplate: "modules/{{ module }}",
+			},
+		}
+
+		// WHEN
+		_, errors := rawCfg.parse(sourceLabels)
+
+		// THEN
+		require.NotEmpty(t, errors)
+		snaps.MatchYAML(t, errors)
+	})
+}
+
+func TestRawDiffConfigsParse(t *testing.T) {
+	sourceLabels := make(map[string]struct{})
+	sourceLabels["dev"] = struct{}{}
+	sourceLabels["staging"] = struct{}{}
+	sourceLabels["prod"] = struct{}{}
+
+	//-------------//
+	//  SUCCESSES  //
+	//-------------//
+
+	t.Run("parsing a single diff config works", func(t *testing.T) {
+		// GIVEN
+		configBytes := []byte(`
+baseLabel: prod
+headLabel: dev
+cmd: ["./scripts/generate-diff.sh"]
+`)
+		var rawCfgs rawDiffConfigs
+		err := yaml.Unmarshal(configBytes, &rawCfgs)
+		require.NoError(t, err)
+
+		// WHEN
+		result, errors := rawCfgs.parse(sourceLabels)
+
+		// THEN
+		require.Empty(t, errors)
+		snaps.MatchYAML(t, result)
+	})
+
+	t.Run("parsing a list of diff configs works", func(t *testing.T) {
+		// GIVEN
+		configBytes := []byte(`
+- baseLabel: staging
+  headLabel: dev
+  cmd: ["./scripts/generate-diff.sh"]
+- baseLabel: prod
+  headLabel: staging
+  cmd: ["./scripts/generate-diff.sh", "--stable"]
+  timeout: 1m
+`)
+		var rawCfgs rawDiffConfigs
+		err := yaml.Unmarshal(configBytes, &rawCfgs)
+		require.NoError(t, err)
+
+		// WHEN
+		result, errors := rawCfgs.parse(sourceLabels)
+
+		// THEN
+		require.Empty(t, errors)
+		snaps.MatchYAML(t, result)
+	})
+
+	//------------//
+	//  FAILURES  //
+	//------------//
+
+	t.Run("parsing an empty list of diff configs fails", func(t *testing.T) {
+		// GIVEN
+		var rawCfgs rawDiffConfigs
+		err := yaml.Unmarshal([]byte(`[]`), &rawCfgs)
+		require.NoError(t, err)
+
+		// WHEN
+		_, errors := rawCfgs.parse(sourceLabels)
+
+		// THEN
+		require.NotEmpty(t, errors)
+		snaps.MatchYAML(t, errors)
+	})
+
+	t.Run("parsing a list with invalid and repeated diff configs fails", func(t *testing.T) {
+		// GIVEN
+		configBytes := []byte(`
+- baseLabel: staging
+  headLabel: dev
+  cmd: ["./scripts/generate-diff.sh"]
+- baseLabel: prod
+  headLabel: unknown
+  cmd: ["./scripts/generate-diff.sh"]
+- baseLabel: staging
+  headLabel: dev
+  cmd: ["./scripts/generate-other-diff.sh"]
+`)
+		var rawCfgs rawDiffConfigs
+		err := yaml.Unmarshal(configBytes, &rawCfgs)
+		require.NoError(t, err)
+
+		// WHEN
+		_, errors := rawCfgs.parse(sourceLabels)
+
+		// THEN
+		require.NotEmpty(t, errors)
+		snaps.MatchYAML(t, errors)
+	})
+}
+
+func TestParsePromotionOrder(t *testing.T) {
+	sourceLabels := make(map[string]struct{})
+	sourceLabels["dev"] = struct{}{}
+	sourceLabels["staging"] = struct{}{}
+	sourceLabels["prod"] = struct{}{}
+
+	//-------------//
+	//  SUCCESSES  //
+	//-------------//
+
+	t.Run("parsing correct promotion order works", func(t *testing.T) {
+		// GIVEN
+		rawOrder := []string{"dev", " staging", "prod "}
+
+		// WHEN
+		result, errors := parsePromotionOrder(rawOrder, sourceLabels)
+
+		// THEN
+		require.Empty(t, errors)
+		require.Equal(t, []string{"dev", "staging", "prod"}, result)
+	})
+
+	//------------//
+	//  FAILURES  //
+	//------------//
+
+	t.Run("parsing promotion order with a single label fails", func(t *testing.T) {
+		// GIVEN
+		rawOrder := []string{"dev"}
+
+		// WHEN
+		_, errors := parsePromotionOrder(rawOrder, sourceLabels)
+
+		// THEN
+		require.NotEmpty(t, errors)
+		snaps.MatchYAML(t, errors)
+	})
+
+	t.Run("parsing promotion order with unknown, empty, and repeated labels fails", func(t *testing.T) {
+		// GIVEN
+		rawOrder := []string{"dev", "qa", " ", "prod", "dev"}
+
+		// WHEN
+		_, errors := parsePromotionOrder(rawOrder, sourceLabels)
+
+		// THEN
+		require.NotEmpty(t, errors)
+		snaps.MatchYAML(t, errors)
+	})
+}
+
+func TestParseAttributeKeys(t *testing.T) {
+	//-------------//
+	//  SUCCESSES  //
+	//-------------//
+
+	t.Run("parsing a single attribute key works", func(t *testing.T) {
+		// GIVEN
+		// WHEN
+		result, errors := parseAttributeKeys(" source ", nil)
+
+		// THEN
+		require.Empty(t, errors)
+		require.Equal(t, []string{"source"}, result)
+	})
+
+	t.Run("parsing attribute paths works", func(t *testing.T) {
+		// GIVEN
+		// WHEN
+		result, errors := parseAttributeKeys("", []string{"tags.team", `tags["cost-center"]`, "node_pools[0].size"})
+
+		// THEN
+		require.Empty(t, errors)
+		require.Equal(t, []string{"tags.team", `tags["cost-center"]`, "node_pools[0].size"}, result)
+	})
+
+	t.Run("parsing a list of attribute keys works", func(t *testing.T) {
+		// GIVEN
+		// WHEN
+		result, errors := parseAttributeKeys("", []string{"version", " source"})
+
+		// THEN
+		require.Empty(t, errors)
+		require.Equal(t, []string{"version", "source"}, result)
+	})
+
+	//------------//
+	//  FAILURES  //
+	//------------//
+
+	t.Run("parsing both a single attribute key and a list fails", func(t *testing.T) {
+		// GIVEN
+		// WHEN
+		_, errors := parseAttributeKeys("source", []string{"version"})
+
+		// THEN
+		require.NotEmpty(t, errors)
+		snaps.MatchYAML(t, errors)
+	})
+
+	t.Run("parsing a list with empty and repeated attribute keys fails", func(t *testing.T) {
+		// GIVEN
+		// WHEN
+		_, errors := parseAttributeKeys("", []string{"source", " ", "version", "source "})
+
+		// THEN
+		require.NotEmpty(t, errors)
+		snaps.MatchYAML(t, errors)
+	})
+	t.Run("parsing invalid attribute paths fails", func(t *testing.T) {
+		// GIVEN
+		// WHEN
+		_, errors := parseAttributeKeys("", []string{"tags.", "node_pools[first]", "tags[\"team", "node pools", ".team"})
+
+		// THEN
+		require.NotEmpty(t, errors)
+		snaps.MatchYAML(t, errors)
+	})
 }
@@ -0,0 +1,117 @@
+package domain
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var semverRegex = regexp.MustCompile(`^[vV]?(0|[1-9]\d*)\.(0|[1-9]\d*)\.(0|[1-9]\d*)(?:-([0-9A-Za-z-]+(?:\.[0-9A-Za-z-]+)*))?(?:\+[0-9A-Za-z-]+(?:\.[0-9A-Za-z-]+)*)?$`)
+
+type Version struct {
+	Major      uint64
+	Minor      uint64
+	Patch      uint64
+	Prerelease string
+}
+
+func ParseVersion(value string) (Version, bool) {
+	matches := semverRegex.FindStringSubmatch(strings.TrimSpace(value))
+	if matches == nil {
+		return Version{}, false
+	}
+
+	var components [3]uint64
+	for i := range components {
+		component, err := strconv.ParseUint(matches[i+1], 10, 64)
+		if err != nil {
+			return Version{}, false
+		}
+		components[i] = component
+	}
+
+	return Version{
+		Major:      components[0],
+		Minor:      components[1],
+		Patch:      components[2],
+		Prerelease: matches[4],
+	}, true
+}
+
+// Compare returns -1, 0, or +1 depending on whether v has a lower, equal, or
+// higher precedence than other, as per the semver spec.
+func (v Version) Compare(other Version) int {
+	switch {
+	case v.Major != other.Major:
+		return compareUint(v.Major, other.Major)
+	case v.Minor != other.Minor:
+		return compareUint(v.Minor, other.Minor)
+	case v.Patch != other.Patch:
+		return compareUint(v.Patch, other.Patch)
+	}
+
+	return comparePrerelease(v.Prerelease, other.Prerelease)
+}
+
+func ClassifyDrift(a, b Version) Drift {
+	switch {
+	case a.Major != b.Major:
+		return DriftMajor
+	case a.Minor != b.Minor:
+		return DriftMinor
+	case a.Patch != b.Patch:
+		return DriftPatch
+	case a.Prerelease != b.Prerelease:
+		return DriftPrerelease
+	default:
+		return DriftNone
+	}
+}
+
+func compareUint(a, b uint64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func comparePrerelease(a, b string) int {
+	switch {
+	case a == b:
+		return 0
+	case a == "":
+		return 1
+	case b == "":
+		return -1
+	}
+
+	aIdentifiers := strings.Split(a, ".")
+	bIdentifiers := strings.Split(b, ".")
+
+	for i := 0; i < len(aIdentifiers) && i < len(bIdentifiers); i++ {
+		aNum, aErr := strconv.ParseUint(aIdentifiers[i], 10, 64)
+		bNum, bErr := strconv.ParseUint(bIdentifiers[i], 10, 64)
+
+		var result int
+		switch {
+		case aErr == nil && bErr == nil:
+			result = compareUint(aNum, bNum)
+		case aErr == nil:
+			result = -1
+		case bErr == nil:
+			result = 1
+		default:
+			result = strings.Compare(aIdentifiers[i], bIdentifiers[i])
+		}
+
+		if result != 0 {
+			return result
+		}
+	}
+
+	return compareUint(uint64(len(aIdentifiers)), uint64(len(bIdentifiers)))
+}
@@ -0,0 +1,131 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseVersion(t *testing.T) {
+	//-------------//
+	//  SUCCESSES  //
+	//-------------//
+
+	t.Run("parsing valid versions works", func(t *testing.T) {
+		// GIVEN
+		cases := map[string]Version{
+			"1.2.3":                {Major: 1, Minor: 2, Patch: 3},
+			"v1.2.3":               {Major: 1, Minor: 2, Patch: 3},
+			"0.10.0-rc.1":          {Major: 0, Minor: 10, Patch: 0, Prerelease: "rc.1"},
+			"2.0.0-beta+build.123": {Major: 2, Minor: 0, Patch: 0, Prerelease: "beta"},
+			" 3.1.4 ":              {Major: 3, Minor: 1, Patch: 4},
+		}
+
+		for input, expected := range cases {
+			// WHEN
+			got, ok := ParseVersion(input)
+
+			// THEN
+			assert.True(t, ok, "input: %q", input)
+			assert.Equal(t, expected, got, "input: %q", input)
+		}
+	})
+
+	//------------//
+	//  FAILURES  //
+	//------------//
+
+	t.Run("parsing invalid versions fails", func(t *testing.T) {
+		// GIVEN
+		cases := []string{
+			"",
+			"1.2",
+			"1.2.3.4",
+			"01.2.3",
+			"main",
+			"module-a-v1.2.3",
+			"1.2.3-",
+		}
+
+		for _, input := range cases {
+			// WHEN
+			_, ok := ParseVersion(input)
+
+			// THEN
+			assert.False(t, ok, "input: %q", input)
+		}
+	})
+}
+
+func TestVersionCompare(t *testing.T) {
+	t.Run("follows semver precedence", func(t *testing.T) {
+		// GIVEN
+		// each version has a lower precedence than the next one
+		ordered := []string{
+			"1.0.0-alpha",
+			"1.0.0-alpha.1",
+			"1.0.0-alpha.beta",
+			"1.0.0-beta",
+			"1.0.0-beta.2",
+			"1.0.0-beta.11",
+			"1.0.0-rc.1",
+			"1.0.0",
+			"1.0.1",
+			"1.1.0",
+			"2.0.0",
+		}
+
+		for i := 0; i < len(ordered)-1; i++ {
+			lower, _ := ParseVersion(ordered[i])
+			higher, _ := ParseVersion(ordered[i+1])
+
+			// WHEN
+			lowerFirst := lower.Compare(higher)
+			higherFirst := higher.Compare(lower)
+
+			// THEN
+			assert.Equal(t, -1, lowerFirst, "%s should be lower than %s", ordered[i], ordered[i+1])
+			assert.Equal(t, 1, higherFirst, "%s should be higher than %s", ordered[i+1], ordered[i])
+		}
+	})
+
+	t.Run("ignores build metadata", func(t *testing.T) {
+		// GIVEN
+		a, _ := ParseVersion("1.0.0+build.1")
+		b, _ := ParseVersion("1.0.0+build.2")
+
+		// WHEN
+		result := a.Compare(b)
+
+		// THEN
+		assert.Equal(t, 0, result)
+	})
+}
+
+func TestClassifyDrift(t *testing.T) {
+	t.Run("classifies drift by the most significant differing component", func(t *testing.T) {
+		// GIVEN
+		cases := []struct {
+			a        string
+			b        string
+			expected Drift
+		}{
+			{"1.0.0", "1.0.0", DriftNone},
+			{"1.0.0", "1.0.0-rc.1", DriftPrerelease},
+			{"1.0.24", "1.0.25", DriftPatch},
+			{"1.1.0", "1.2.5", DriftMinor},
+			{"1.9.9", "3.0.0", DriftMajor},
+		}
+
+		for _, c := range cases {
+			a, _ := ParseVersion(c.a)
+			b, _ := ParseVersion(c.b)
+
+			// WHEN
+			got := ClassifyDrift(a, b)
+
+			// THEN
+			assert.Equal(t, c.expected, got, "%s vs %s", c.a, c.b)
+		}
+	})
+}
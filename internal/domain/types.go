@@ -6,6 +6,9 @@ const (
 	StatusInSync ModuleStatus = iota
 	StatusOutOfSync
 	StatusNotApplicable
+	// StatusAheadOfUpstream is used when a module has a newer version in a
+	// label than in the one preceding it in the comparison's promotion order
+	StatusAheadOfUpstream
 )
 
 func (s ModuleStatus) String() string {
@@ -16,6 +19,8 @@ func (s ModuleStatus) String() string {
 		return "out_of_sync"
 	case StatusNotApplicable:
 		return "not_applicable"
+	case StatusAheadOfUpstream:
+		return "ahead_of_upstream"
 	default:
 		return "not_applicable"
 	}
@@ -29,27 +34,286 @@ func (s ModuleStatus) Symbol() string {
 		return "✗"
 	case StatusNotApplicable:
 		return "-"
+	case StatusAheadOfUpstream:
+		return "↑"
 	default:
 		return "-"
 	}
 }
 
+type Drift uint8
+
+const (
+	DriftNone Drift = iota
+	DriftPrerelease
+	DriftPatch
+	DriftMinor
+	DriftMajor
+	// DriftUnknown is used when values couldn't be parsed as semantic versions
+	DriftUnknown
+)
+
+func (d Drift) String() string {
+	switch d {
+	case DriftPrerelease:
+		return "prerelease"
+	case DriftPatch:
+		return "patch"
+	case DriftMinor:
+		return "minor"
+	case DriftMajor:
+		return "major"
+	case DriftUnknown:
+		return "unknown"
+	default:
+		return "none"
+	}
+}
+
+func ParseDrift(value string) (Drift, bool) {
+	switch value {
+	case "prerelease":
+		return DriftPrerelease, true
+	case "patch":
+		return DriftPatch, true
+	case "minor":
+		return DriftMinor, true
+	case "major":
+		return DriftMajor, true
+	default:
+		return DriftNone, false
+	}
+}
+
+func GetDriftValues() []string {
+	return []string{"major", "minor", "patch", "prerelease"}
+}
+
 type DiffResult struct {
 	Output    []byte
 	BaseLabel string
 	HeadLabel string
 	BaseRef   string
 	HeadRef   string
+	// commits between the base and head refs, if a log was collected
+	Commits []Commit `yaml:"commits,omitempty"`
+	// whether Output is pre-rendered HTML
+	OutputIsHTML bool `yaml:"outputIsHTML,omitempty"`
+}
+
+type Commit struct {
+	Hash    string
+	Subject string
+}
+
+// DiffError records why a diff couldn't be computed for a module
+type DiffError struct {
+	BaseLabel string
+	HeadLabel string
+	BaseRef   string
+	HeadRef   string
+	// -1 if the command didn't exit on its own (eg. it timed out)
+	ExitCode int
+	Message  string
+	// the tail end of the command's stderr
+	Stderr string `yaml:"stderr,omitempty"`
 }
 
 type ModuleResult struct {
-	Name       string
+	Name   string
+	Values map[string]string
+	Status ModuleStatus
+	Drift  Drift `yaml:"drift,omitempty"`
+	// set when the module is absent from some of the labels
+	Missing bool `yaml:"missing,omitempty"`
+	// reasons why values couldn't be resolved, by label; such labels are
+	// absent from Values
+	Unresolved map[string]string `yaml:"unresolved,omitempty"`
+	// parts of complex values (objects, maps, and lists) that differ between
+	// labels; only set for modules that are out of sync
+	ValueDiffs []ValueDiff `yaml:"valueDiffs,omitempty"`
+	// values of every attribute, by attribute key; only set for comparisons
+	// with more than one attribute (Values and Unresolved hold the ones for
+	// the primary attribute)
+	Attributes map[string]AttributeValues `yaml:"attributes,omitempty"`
+	// one per diff config whose diff could be computed, in config order
+	DiffResults []DiffResult `yaml:"diffResults,omitempty"`
+	// one per diff config whose diff couldn't be computed, in config order
+	DiffErrors []DiffError `yaml:"diffErrors,omitempty"`
+}
+
+// ValueDiff is a value nested in a complex value (eg. node_pools[0].size) that
+// differs between labels
+type ValueDiff struct {
+	Path string
+	// JSON encoded, by label; labels whose values don't have the path are
+	// absent
+	Values map[string]string
+}
+
+type AttributeValues struct {
 	Values     map[string]string
-	Status     ModuleStatus
-	DiffResult *DiffResult `yaml:"diffResult,omitempty"`
+	Unresolved map[string]string `yaml:"unresolved,omitempty"`
+}
+
+// ResultKind is what the entries of a comparison result are
+type ResultKind uint8
+
+const (
+	ModulesResult ResultKind = iota
+	// ProvidersResult is used for the version constraints of providers, and of
+	// terraform itself
+	ProvidersResult
+)
+
+func (k ResultKind) String() string {
+	switch k {
+	case ProvidersResult:
+		return "providers"
+	default:
+		return "modules"
+	}
+}
+
+// Noun returns what a single entry of a result of this kind is called
+func (k ResultKind) Noun() string {
+	switch k {
+	case ProvidersResult:
+		return "provider"
+	default:
+		return "module"
+	}
 }
 
 type ComparisonResult struct {
+	Name         string
+	Kind         ResultKind `yaml:"kind,omitempty"`
+	SourceLabels []string
+	// only set when more than one attribute is compared
+	AttributeKeys []string `yaml:"attributeKeys,omitempty"`
+	Modules       []ModuleResult
+	SemverCfg     *SemverConfig `yaml:"semverCfg,omitempty"`
+	// whether the absence of a module from some labels was ignored
+	IgnoreMissingModules bool `yaml:"ignoreMissingModules,omitempty"`
+}
+
+// HasFailingModules reports whether any of the modules in the result should
+// lead to a failure.
+func (r ComparisonResult) HasFailingModules() bool {
+	for _, module := range r.Modules {
+		if r.IsModuleFailing(module) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (r ComparisonResult) HasDiffErrors() bool {
+	for _, module := range r.Modules {
+		if len(module.DiffErrors) > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (r ComparisonResult) HasModulesAheadOfUpstream() bool {
+	for _, module := range r.Modules {
+		if module.Status == StatusAheadOfUpstream {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsModuleFailing reports whether a module should lead to a failure. When
+// semver classification is enabled, modules that drift less than the
+// configured severity are not considered failures, unless they're missing from
+// some labels (and that isn't ignored).
+func (r ComparisonResult) IsModuleFailing(module ModuleResult) bool {
+	if module.Status == StatusAheadOfUpstream {
+		return true
+	}
+
+	if module.Status != StatusOutOfSync {
+		return false
+	}
+
+	if r.SemverCfg == nil {
+		return true
+	}
+
+	if module.Missing && !r.IgnoreMissingModules {
+		return true
+	}
+
+	return module.Drift == DriftUnknown || module.Drift >= r.SemverCfg.FailOn
+}
+
+// ArgumentStatus is the status of an argument of a module across the labels
+// the module is present in.
+type ArgumentStatus uint8
+
+const (
+	ArgumentSame ArgumentStatus = iota
+	ArgumentDiffers
+	// ArgumentMissing is used when an argument isn't set in every label the
+	// module is present in; it takes precedence over ArgumentDiffers
+	ArgumentMissing
+)
+
+func (s ArgumentStatus) String() string {
+	switch s {
+	case ArgumentDiffers:
+		return "differs"
+	case ArgumentMissing:
+		return "missing"
+	default:
+		return "same"
+	}
+}
+
+type ModuleArgument struct {
+	Name string
+	// normalized expression text, by label; labels the argument isn't set in
+	// are absent
+	Values map[string]string
+	Status ArgumentStatus
+}
+
+type ModuleInputs struct {
+	Name string
+	// labels the module is present in, in source order
+	Labels    []string
+	Arguments []ModuleArgument
+}
+
+type InputsResult struct {
+	Name         string
 	SourceLabels []string
-	Modules      []ModuleResult
+	// only modules present in two or more labels
+	Modules []ModuleInputs
+}
+
+type SyncChange struct {
+	Module   string
+	File     string
+	OldValue string
+	NewValue string
+}
+
+type FileChange struct {
+	Path   string
+	Before []byte
+	After  []byte
+}
+
+type SyncPlan struct {
+	FromLabel string
+	ToLabel   string
+	Changes   []SyncChange
+	Files     []FileChange
 }
@@ -4,9 +4,11 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
 	"regexp"
 	"strings"
 
+	"github.com/dhth/tflens/internal/utils"
 	yaml "github.com/goccy/go-yaml"
 )
 
@@ -61,10 +63,8 @@ func parseRawConfig(raw rawConfig) (Config, error) {
 			comparisonErrors = append(comparisonErrors, "comparison has an empty name")
 		}
 
-		attributeKey := strings.TrimSpace(comparison.AttributeKey)
-		if len(attributeKey) == 0 {
-			comparisonErrors = append(comparisonErrors, "comparison has an empty attribute key")
-		}
+		attributeKeys, attributeKeysErrors := parseAttributeKeys(comparison.AttributeKey, comparison.AttributeKeys)
+		comparisonErrors = append(comparisonErrors, attributeKeysErrors...)
 
 		if len(comparison.Sources) <= 1 {
 			comparisonErrors = append(comparisonErrors, "comparison needs to have at least 2 sources")
@@ -90,61 +90,112 @@ func parseRawConfig(raw rawConfig) (Config, error) {
 				sourceLabels[trimmedLabel] = struct{}{}
 			}
 
-			pathOk := false
 			if len(strings.TrimSpace(source.Path)) == 0 {
 				comparisonErrors = append(comparisonErrors, fmt.Sprintf("source #%d is empty", s+1))
 				continue
 			}
 
-			if !strings.HasSuffix(source.Path, ".tf") {
-				comparisonErrors = append(comparisonErrors, fmt.Sprintf("source #%d should have the extension .tf", s+1))
+			var sourcePattern *regexp.Regexp
+			sourcePatternOk := true
+			if source.ValueRegex != "" {
+				sourcePattern, err = regexp.Compile(source.ValueRegex)
+				if err != nil {
+					comparisonErrors = append(comparisonErrors, fmt.Sprintf("source #%d has an invalid valueRegex: %s", s+1, err.Error()))
+					sourcePatternOk = false
+				}
+			}
+
+			kind, kindOk := ParseSourceKind(strings.TrimSpace(source.Kind))
+			if !kindOk {
+				comparisonErrors = append(comparisonErrors,
+					fmt.Sprintf("source #%d has an invalid kind %q; allowed values: %v", s+1, source.Kind, GetSourceKindValues()),
+				)
 				continue
 			}
 
-			_, err := os.Stat(source.Path)
-			if os.IsNotExist(err) {
-				comparisonErrors = append(comparisonErrors, fmt.Sprintf("source #%d does not exist: %s", s+1, source.Path))
-			} else if err != nil {
-				comparisonErrors = append(comparisonErrors, fmt.Sprintf("couldn't check if source #%d exists: %s", s+1, err.Error()))
-			} else {
-				pathOk = true
+			ref := strings.TrimSpace(source.Ref)
+			refOk := true
+			if strings.HasPrefix(ref, "-") {
+				comparisonErrors = append(comparisonErrors, fmt.Sprintf("source #%d has an invalid ref %q", s+1, ref))
+				refOk = false
+			}
+
+			var pathErr string
+			switch {
+			case ref != "":
+				// the path is resolved against the ref when the source is read
+				pathErr = validateRefSourcePath(strings.TrimSpace(source.Path))
+			case kind == TerragruntSource:
+				pathErr = validateTerragruntSourcePath(strings.TrimSpace(source.Path))
+			default:
+				pathErr = validateSourcePath(strings.TrimSpace(source.Path))
+			}
+			if pathErr != "" {
+				comparisonErrors = append(comparisonErrors, fmt.Sprintf("source #%d %s", s+1, pathErr))
+			}
+			pathOk := pathErr == ""
+
+			varFiles, varFilesErrors := validateVarFiles(source.VarFiles, kind, ref)
+			for _, err := range varFilesErrors {
+				comparisonErrors = append(comparisonErrors, fmt.Sprintf("source #%d %s", s+1, err))
 			}
+			varFilesOk := len(varFilesErrors) == 0
 
-			if labelOk && pathOk {
+			if labelOk && pathOk && sourcePatternOk && refOk && varFilesOk {
 				validatedSource := Source{
-					Path:  strings.TrimSpace(source.Path),
-					Label: strings.TrimSpace(source.Label),
+					Path:       strings.TrimSpace(source.Path),
+					Label:      strings.TrimSpace(source.Label),
+					Kind:       kind,
+					ValueRegex: sourcePattern,
+					Ref:        ref,
+					VarFiles:   varFiles,
 				}
 				validatedSources = append(validatedSources, validatedSource)
 			}
 		}
 
-		var diffCfgToUse *DiffConfig
-		if comparison.DiffCfg != nil {
-			diffCfg, diffErrors := comparison.DiffCfg.parse(sourceLabels)
-			if len(diffErrors) > 0 {
-				diffErrorStrs := make([]string, 0, len(diffErrors))
-				for _, err := range diffErrors {
-					diffErrorStrs = append(diffErrorStrs, fmt.Sprintf("    - %s", err))
+		var diffCfgsToUse []DiffConfig
+		if comparison.DiffCfgs != nil {
+			var diffCfgsErrors []string
+			diffCfgsToUse, diffCfgsErrors = comparison.DiffCfgs.parse(sourceLabels)
+			comparisonErrors = append(comparisonErrors, diffCfgsErrors...)
+		}
+
+		var semverCfgToUse *SemverConfig
+		if comparison.SemverCfg != nil {
+			semverCfg, semverErrors := comparison.SemverCfg.parse()
+			if len(semverErrors) > 0 {
+				semverErrorStrs := make([]string, 0, len(semverErrors))
+				for _, err := range semverErrors {
+					semverErrorStrs = append(semverErrorStrs, fmt.Sprintf("    - %s", err))
 				}
 				comparisonErrors = append(comparisonErrors,
-					fmt.Sprintf("diffConfig has errors:\n%s", strings.Join(diffErrorStrs, "\n")),
+					fmt.Sprintf("semver has errors:\n%s", strings.Join(semverErrorStrs, "\n")),
 				)
 			} else {
-				diffCfgToUse = &diffCfg
+				semverCfgToUse = &semverCfg
 			}
 		}
 
+		var promotionOrder []string
+		if len(comparison.PromotionOrder) > 0 {
+			var promotionErrors []string
+			promotionOrder, promotionErrors = parsePromotionOrder(comparison.PromotionOrder, sourceLabels)
+			comparisonErrors = append(comparisonErrors, promotionErrors...)
+		}
+
 		if len(comparisonErrors) > 0 {
 			errors = append(errors, comparisonValidationErrors{index: c, errors: comparisonErrors})
 		} else {
 			validatedComparison := Comparison{
-				Name:          comparisonName,
-				AttributeKey:  attributeKey,
-				Sources:       validatedSources,
-				IgnoreModules: comparison.IgnoreModules,
-				ValueRegex:    comparisonPattern,
-				DiffCfg:       diffCfgToUse,
+				Name:           comparisonName,
+				AttributeKeys:  attributeKeys,
+				Sources:        validatedSources,
+				IgnoreModules:  comparison.IgnoreModules,
+				ValueRegex:     comparisonPattern,
+				DiffCfgs:       diffCfgsToUse,
+				SemverCfg:      semverCfgToUse,
+				PromotionOrder: promotionOrder,
 			}
 
 			validatedConfig.CompareModules.Comparisons = append(validatedConfig.CompareModules.Comparisons, validatedComparison)
@@ -171,3 +222,202 @@ func parseRawConfig(raw rawConfig) (Config, error) {
 
 	return validatedConfig, nil
 }
+
+func validateSourcePath(path string) string {
+	if utils.HasGlobMeta(path) {
+		matches, err := utils.Glob(utils.OSFS{}, path)
+		if err != nil {
+			return fmt.Sprintf("has an invalid glob pattern: %s", err.Error())
+		}
+
+		for _, match := range matches {
+			if strings.HasSuffix(match, ".tf") {
+				return ""
+			}
+		}
+
+		return fmt.Sprintf("doesn't match any .tf files: %s", path)
+	}
+
+	info, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		return fmt.Sprintf("does not exist: %s", path)
+	} else if err != nil {
+		return fmt.Sprintf("couldn't be checked: %s", err.Error())
+	}
+
+	if info.IsDir() {
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			return fmt.Sprintf("couldn't be read: %s", err.Error())
+		}
+
+		for _, entry := range entries {
+			if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".tf") {
+				return ""
+			}
+		}
+
+		return fmt.Sprintf("is a directory without any .tf files: %s", path)
+	}
+
+	if !strings.HasSuffix(path, ".tf") {
+		return "should have the extension .tf"
+	}
+
+	return ""
+}
+
+func validateVarFiles(varFiles []string, kind SourceKind, ref string) ([]string, []string) {
+	if len(varFiles) == 0 {
+		return nil, nil
+	}
+
+	if kind != TerraformSource {
+		return nil, []string{"can only have varFiles if it's a terraform source"}
+	}
+
+	var errors []string
+	trimmed := make([]string, 0, len(varFiles))
+	for i, varFile := range varFiles {
+		path := strings.TrimSpace(varFile)
+		switch {
+		case path == "":
+			errors = append(errors, fmt.Sprintf("has an empty var file at varFiles[%d]", i+1))
+			continue
+		case ref != "":
+			// var files are read from the ref as well
+			if filepath.IsAbs(path) {
+				errors = append(errors, fmt.Sprintf("needs var files to be relative paths when a ref is specified: %s", path))
+				continue
+			}
+		default:
+			info, err := os.Stat(path)
+			if os.IsNotExist(err) {
+				errors = append(errors, fmt.Sprintf("has a var file that does not exist: %s", path))
+				continue
+			} else if err != nil {
+				errors = append(errors, fmt.Sprintf("has a var file that couldn't be checked: %s", err.Error()))
+				continue
+			}
+			if info.IsDir() {
+				errors = append(errors, fmt.Sprintf("has a var file that is a directory: %s", path))
+				continue
+			}
+		}
+		trimmed = append(trimmed, path)
+	}
+
+	return trimmed, errors
+}
+
+func validateRefSourcePath(path string) string {
+	if filepath.IsAbs(path) {
+		return fmt.Sprintf("needs to be a relative path when a ref is specified: %s", path)
+	}
+
+	if utils.HasGlobMeta(path) {
+		err := utils.ValidateGlob(path)
+		if err != nil {
+			return fmt.Sprintf("has an invalid glob pattern: %s", err.Error())
+		}
+	}
+
+	return ""
+}
+
+func validateTerragruntSourcePath(path string) string {
+	info, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		return fmt.Sprintf("does not exist: %s", path)
+	} else if err != nil {
+		return fmt.Sprintf("couldn't be checked: %s", err.Error())
+	}
+
+	if !info.IsDir() {
+		return fmt.Sprintf("should be a directory for terragrunt sources: %s", path)
+	}
+
+	return ""
+}
+
+func parsePromotionOrder(rawOrder []string, labels map[string]struct{}) ([]string, []string) {
+	var errors []string
+
+	if len(rawOrder) < 2 {
+		errors = append(errors, "promotionOrder needs to have at least 2 labels")
+	}
+
+	order := make([]string, 0, len(rawOrder))
+	seen := make(map[string]struct{})
+	for i, rawLabel := range rawOrder {
+		label := strings.TrimSpace(rawLabel)
+		if len(label) == 0 {
+			errors = append(errors, fmt.Sprintf("promotionOrder[%d] is empty", i+1))
+			continue
+		}
+
+		if _, ok := labels[label]; !ok {
+			errors = append(errors, fmt.Sprintf("promotionOrder label %q is not in the list of defined labels", label))
+			continue
+		}
+
+		if _, ok := seen[label]; ok {
+			errors = append(errors, fmt.Sprintf("promotionOrder label %q is repeated", label))
+			continue
+		}
+		seen[label] = struct{}{}
+
+		order = append(order, label)
+	}
+
+	return order, errors
+}
+
+// parseAttributeKeys accepts either a single attribute key, or a list of them;
+// keys can be paths to values nested in attributes (eg. tags.team)
+func parseAttributeKeys(rawKey string, rawKeys []string) ([]string, []string) {
+	var errors []string
+
+	if rawKey != "" && len(rawKeys) > 0 {
+		return nil, []string{"comparison can only have one of attributeKey and attributeKeys"}
+	}
+
+	if len(rawKeys) == 0 {
+		key := strings.TrimSpace(rawKey)
+		if len(key) == 0 {
+			return nil, []string{"comparison has an empty attribute key"}
+		}
+
+		if _, err := ParseAttributePath(key); err != nil {
+			return nil, []string{err.Error()}
+		}
+
+		return []string{key}, nil
+	}
+
+	keys := make([]string, 0, len(rawKeys))
+	seen := make(map[string]struct{})
+	for i, rawKey := range rawKeys {
+		key := strings.TrimSpace(rawKey)
+		if len(key) == 0 {
+			errors = append(errors, fmt.Sprintf("attributeKeys[%d] is empty", i+1))
+			continue
+		}
+
+		if _, err := ParseAttributePath(key); err != nil {
+			errors = append(errors, err.Error())
+			continue
+		}
+
+		if _, ok := seen[key]; ok {
+			errors = append(errors, fmt.Sprintf("attribute key %q is repeated", key))
+			continue
+		}
+		seen[key] = struct{}{}
+
+		keys = append(keys, key)
+	}
+
+	return keys, errors
+}
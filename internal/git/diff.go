@@ -0,0 +1,26 @@
+package git
+
+// DiffCommand returns the command that shows the changes between baseRef and
+// headRef in the repository at repoPath, limited to path if it's not empty.
+// The output is kept free of colours and external diff drivers, as it is
+// rendered by tflens.
+func DiffCommand(repoPath, baseRef, headRef, path string) []string {
+	command := []string{"git", "-C", repoPath, "diff", "--no-color", "--no-ext-diff", "--end-of-options", baseRef + ".." + headRef}
+	if path != "" {
+		command = append(command, "--", path)
+	}
+
+	return command
+}
+
+// LogCommand returns the command that lists the commits reachable from headRef
+// but not from baseRef in the repository at repoPath, one per line, limited to
+// the ones touching path if it's not empty.
+func LogCommand(repoPath, baseRef, headRef, path string) []string {
+	command := []string{"git", "-C", repoPath, "log", "--oneline", "--no-decorate", "--no-color", "--end-of-options", baseRef + ".." + headRef}
+	if path != "" {
+		command = append(command, "--", path)
+	}
+
+	return command
+}
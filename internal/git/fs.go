@@ -0,0 +1,187 @@
+package git
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+var (
+	ErrCouldntListRef = errors.New("couldn't list files at git ref")
+	ErrCouldntReadRef = errors.New("couldn't read file at git ref")
+)
+
+// waitDelay bounds how long to wait for a git command's output pipes to be
+// closed after it has been killed.
+const waitDelay = 5 * time.Second
+
+// FS provides read-only access to the files under a path at a git ref, as
+// recorded in the local repository's object store. Like with the working tree,
+// paths are relative to the current directory.
+type FS struct {
+	// git commands run by the FS are killed when ctx is done; fs.FS methods
+	// don't take a context, hence it's held here
+	ctx context.Context
+	ref string
+	//          path   object name
+	blobs map[string]string
+	dirs  map[string][]fs.DirEntry
+}
+
+// NewFS lists all files under root at ref. Only files under root can be read
+// via the returned FS. Reading files stops once ctx is done.
+func NewFS(ctx context.Context, ref, root string) (*FS, error) {
+	root = filepath.Clean(root)
+
+	output, err := run(ctx, "ls-tree", "-r", "-z", ref, "--", root)
+	if err != nil {
+		return nil, fmt.Errorf("%w (ref: %q, path: %q): %w", ErrCouldntListRef, ref, root, err)
+	}
+
+	fsys := &FS{
+		ctx:   ctx,
+		ref:   ref,
+		blobs: make(map[string]string),
+		dirs:  make(map[string][]fs.DirEntry),
+	}
+
+	for _, line := range strings.Split(string(output), "\x00") {
+		// <mode> SP <type> SP <object> TAB <file>
+		meta, name, ok := strings.Cut(line, "\t")
+		if !ok {
+			continue
+		}
+
+		fields := strings.Fields(meta)
+		if len(fields) != 3 || fields[1] != "blob" {
+			continue
+		}
+
+		fsys.addBlob(filepath.Clean(filepath.FromSlash(name)), fields[2], root)
+	}
+
+	for dir := range fsys.dirs {
+		slices.SortFunc(fsys.dirs[dir], func(a, b fs.DirEntry) int {
+			return strings.Compare(a.Name(), b.Name())
+		})
+	}
+
+	return fsys, nil
+}
+
+func (f *FS) Open(name string) (fs.File, error) {
+	return nil, &fs.PathError{Op: "open", Path: name, Err: errors.ErrUnsupported}
+}
+
+func (f *FS) Stat(name string) (fs.FileInfo, error) {
+	name = filepath.Clean(name)
+
+	if _, ok := f.blobs[name]; ok {
+		return entry{name: filepath.Base(name)}, nil
+	}
+
+	if _, ok := f.dirs[name]; ok {
+		return entry{name: filepath.Base(name), dir: true}, nil
+	}
+
+	return nil, &fs.PathError{Op: "stat", Path: name, Err: fs.ErrNotExist}
+}
+
+func (f *FS) ReadDir(name string) ([]fs.DirEntry, error) {
+	entries, ok := f.dirs[filepath.Clean(name)]
+	if !ok {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrNotExist}
+	}
+
+	return slices.Clone(entries), nil
+}
+
+func (f *FS) ReadFile(name string) ([]byte, error) {
+	object, ok := f.blobs[filepath.Clean(name)]
+	if !ok {
+		return nil, &fs.PathError{Op: "read", Path: name, Err: fs.ErrNotExist}
+	}
+
+	content, err := run(f.ctx, "cat-file", "blob", object)
+	if err != nil {
+		return nil, fmt.Errorf("%w (ref: %q, path: %q): %w", ErrCouldntReadRef, f.ref, name, err)
+	}
+
+	return content, nil
+}
+
+func (f *FS) addBlob(name, object, root string) {
+	f.blobs[name] = object
+
+	child := entry{name: filepath.Base(name)}
+	for dir := filepath.Dir(name); ; dir = filepath.Dir(dir) {
+		_, seen := f.dirs[dir]
+		f.dirs[dir] = append(f.dirs[dir], child)
+
+		if seen || dir == root || dir == "." || dir == filepath.Dir(dir) {
+			return
+		}
+
+		child = entry{name: filepath.Base(dir), dir: true}
+	}
+}
+
+func run(ctx context.Context, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.WaitDelay = waitDelay
+
+	var stdoutBuf, stderrBuf bytes.Buffer
+	cmd.Stdout = &stdoutBuf
+	cmd.Stderr = &stderrBuf
+
+	err := cmd.Run()
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		stderr := strings.TrimSpace(stderrBuf.String())
+		if stderr != "" {
+			return nil, fmt.Errorf("%w: %s", err, stderr)
+		}
+		return nil, err
+	}
+
+	return stdoutBuf.Bytes(), nil
+}
+
+// entry implements both fs.FileInfo and fs.DirEntry for files and directories
+// in a git tree.
+type entry struct {
+	name string
+	dir  bool
+}
+
+func (e entry) Name() string { return e.name }
+
+func (e entry) IsDir() bool { return e.dir }
+
+func (e entry) Type() fs.FileMode { return e.Mode().Type() }
+
+func (e entry) Info() (fs.FileInfo, error) { return e, nil }
+
+func (e entry) Size() int64 { return 0 }
+
+func (e entry) ModTime() time.Time { return time.Time{} }
+
+func (e entry) Sys() any { return nil }
+
+func (e entry) Mode() fs.FileMode {
+	if e.dir {
+		return fs.ModeDir | 0o555
+	}
+
+	return 0o444
+}
@@ -0,0 +1,110 @@
+package hcl
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dhth/tflens/internal/utils"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+)
+
+// TFModuleArguments holds the arguments set in a module block.
+type TFModuleArguments struct {
+	Name string
+	// normalized expression text, by argument name
+	Arguments map[string]string
+	File      string
+}
+
+// ParseModuleArguments reads the arguments of the modules declared in a
+// source. Expressions aren't evaluated; their text is formatted the way
+// "terraform fmt" would, and comments are dropped, so that only changes to
+// the expressions themselves lead to differing text.
+func ParseModuleArguments(fsys utils.FS, path string) ([]TFModuleArguments, error) {
+	files, err := ResolveFiles(fsys, path)
+	if err != nil {
+		return nil, err
+	}
+
+	var modules []TFModuleArguments
+	declaredAt := make(map[string]hcl.Range)
+
+	for _, file := range files {
+		content, err := fsys.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("%w (%q): %w", ErrCouldntReadFile, file, err)
+		}
+
+		parsed, diags := hclwrite.ParseConfig(content, file, hcl.InitialPos)
+		if diags.HasErrors() {
+			return nil, fmt.Errorf("%w (%q): %s", ErrCouldntParseFile, file, diags.Error())
+		}
+
+		// hclwrite doesn't expose the ranges of blocks, which are needed for
+		// reporting duplicates
+		syntaxFile, diags := hclsyntax.ParseConfig(content, file, hcl.InitialPos)
+		if diags.HasErrors() {
+			return nil, fmt.Errorf("%w (%q): %s", ErrCouldntParseFile, file, diags.Error())
+		}
+		body, ok := syntaxFile.Body.(*hclsyntax.Body)
+		if !ok {
+			return nil, ErrUnexpectedBodyType
+		}
+
+		for _, block := range body.Blocks {
+			if block.Type != "module" {
+				continue
+			}
+
+			if len(block.Labels) == 0 {
+				return nil, fmt.Errorf("%w at %s", ErrModuleMissingLabel, block.DefRange())
+			}
+			moduleName := block.Labels[0]
+
+			if previous, ok := declaredAt[moduleName]; ok {
+				return nil, fmt.Errorf("%w: %q is declared at %s and %s", ErrDuplicateModule, moduleName, previous, block.DefRange())
+			}
+			declaredAt[moduleName] = block.DefRange()
+
+			writeBlock := parsed.Body().FirstMatchingBlock("module", []string{moduleName})
+			if writeBlock == nil {
+				return nil, fmt.Errorf("%w: module %q in %q", ErrBlockNotFound, moduleName, file)
+			}
+
+			arguments := make(map[string]string)
+			for name, attr := range writeBlock.Body().Attributes() {
+				arguments[name] = normalizeExpression(attr.Expr().BuildTokens(nil))
+			}
+
+			modules = append(modules, TFModuleArguments{
+				Name:      moduleName,
+				Arguments: arguments,
+				File:      file,
+			})
+		}
+	}
+
+	return modules, nil
+}
+
+func normalizeExpression(tokens hclwrite.Tokens) string {
+	var withoutComments hclwrite.Tokens
+	for _, token := range tokens {
+		if token.Type != hclsyntax.TokenComment {
+			withoutComments = append(withoutComments, token)
+			continue
+		}
+
+		// line comments include the newline that ends them
+		if strings.HasSuffix(string(token.Bytes), "\n") {
+			withoutComments = append(withoutComments, &hclwrite.Token{
+				Type:  hclsyntax.TokenNewline,
+				Bytes: []byte("\n"),
+			})
+		}
+	}
+
+	return strings.TrimSpace(string(hclwrite.Format(withoutComments.Bytes())))
+}
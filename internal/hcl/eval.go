@@ -0,0 +1,294 @@
+package hcl
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/dhth/tflens/internal/utils"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/function"
+	"github.com/zclconf/go-cty/cty/function/stdlib"
+)
+
+var (
+	ErrCouldntParseVarFile = errors.New("couldn't parse var file")
+	ErrDuplicateLocal      = errors.New("local declared more than once")
+	ErrDuplicateVariable   = errors.New("variable declared more than once")
+	ErrUnresolvableValue   = errors.New("couldn't resolve value")
+)
+
+// the subset of terraform's functions that are commonly used to build
+// attribute values, and that have an equivalent in cty's standard library
+var evalFunctions = map[string]function.Function{
+	"coalesce":   stdlib.CoalesceFunc,
+	"concat":     stdlib.ConcatFunc,
+	"element":    stdlib.ElementFunc,
+	"format":     stdlib.FormatFunc,
+	"join":       stdlib.JoinFunc,
+	"length":     stdlib.LengthFunc,
+	"lookup":     stdlib.LookupFunc,
+	"lower":      stdlib.LowerFunc,
+	"merge":      stdlib.MergeFunc,
+	"replace":    stdlib.ReplaceFunc,
+	"split":      stdlib.SplitFunc,
+	"substr":     stdlib.SubstrFunc,
+	"trim":       stdlib.TrimFunc,
+	"trimprefix": stdlib.TrimPrefixFunc,
+	"trimspace":  stdlib.TrimSpaceFunc,
+	"trimsuffix": stdlib.TrimSuffixFunc,
+	"upper":      stdlib.UpperFunc,
+}
+
+// VarFile is a .tfvars file that sets values for a source's variables.
+type VarFile struct {
+	Path    string
+	Content []byte
+}
+
+// evaluator resolves expressions that refer to the locals and variables
+// declared in a source. Locals are evaluated lazily, as only the ones that
+// attribute values refer to need to be resolvable.
+type evaluator struct {
+	locals    map[string]*hclsyntax.Attribute
+	variables map[string]cty.Value
+	// why the defaults of variables that have one couldn't be evaluated
+	invalidDefaults map[string]error
+	resolved        map[string]cty.Value
+	failed          map[string]error
+}
+
+func newEvaluator() *evaluator {
+	return &evaluator{
+		locals:          make(map[string]*hclsyntax.Attribute),
+		variables:       make(map[string]cty.Value),
+		invalidDefaults: make(map[string]error),
+		resolved:        make(map[string]cty.Value),
+		failed:          make(map[string]error),
+	}
+}
+
+// addLocals records the locals declared in a body's locals blocks.
+func (e *evaluator) addLocals(body *hclsyntax.Body) error {
+	for _, block := range body.Blocks {
+		if block.Type != "locals" {
+			continue
+		}
+
+		for name, attr := range block.Body.Attributes {
+			if previous, ok := e.locals[name]; ok {
+				return fmt.Errorf("%w: %q is declared at %s and %s", ErrDuplicateLocal, name, previous.NameRange, attr.NameRange)
+			}
+			e.locals[name] = attr
+		}
+	}
+
+	return nil
+}
+
+// addVariables records the defaults of the variables declared in a body.
+// Variables without a default only get a value via a var file.
+func (e *evaluator) addVariables(body *hclsyntax.Body, declared map[string]hcl.Range) error {
+	for _, block := range body.Blocks {
+		if block.Type != "variable" || len(block.Labels) == 0 {
+			continue
+		}
+
+		name := block.Labels[0]
+		if previous, ok := declared[name]; ok {
+			return fmt.Errorf("%w: %q is declared at %s and %s", ErrDuplicateVariable, name, previous, block.DefRange())
+		}
+		declared[name] = block.DefRange()
+
+		attr, ok := block.Body.Attributes["default"]
+		if !ok {
+			continue
+		}
+
+		value, diags := attr.Expr.Value(nil)
+		if diags.HasErrors() {
+			// an invalid default is only an issue for values that refer to it
+			e.invalidDefaults[name] = fmt.Errorf("%w: default of variable %q couldn't be evaluated: %s", ErrUnresolvableValue, name, diags.Error())
+			continue
+		}
+		e.variables[name] = value
+	}
+
+	return nil
+}
+
+// addVarFiles sets variables to the values in var files; values in later files
+// take precedence over earlier ones, and over defaults.
+func (e *evaluator) addVarFiles(parser *hclparse.Parser, varFiles []VarFile) error {
+	for _, varFile := range varFiles {
+		file, diags := parser.ParseHCL(varFile.Content, varFile.Path)
+		if diags.HasErrors() {
+			return fmt.Errorf("%w (%q): %s", ErrCouldntParseVarFile, varFile.Path, diags.Error())
+		}
+
+		attrs, diags := file.Body.JustAttributes()
+		if diags.HasErrors() {
+			return fmt.Errorf("%w (%q): %s", ErrCouldntParseVarFile, varFile.Path, diags.Error())
+		}
+
+		for name, attr := range attrs {
+			value, diags := attr.Expr.Value(nil)
+			if diags.HasErrors() {
+				return fmt.Errorf("%w (%q): %s", ErrCouldntParseVarFile, varFile.Path, diags.Error())
+			}
+			e.variables[name] = value
+		}
+	}
+
+	return nil
+}
+
+// value evaluates an expression; visiting holds the locals being evaluated
+// further up the stack, and is used to detect cycles.
+func (e *evaluator) value(expr hcl.Expression, visiting []string) (cty.Value, error) {
+	locals := make(map[string]cty.Value)
+	for _, traversal := range expr.Variables() {
+		root := traversal.RootName()
+		name, ok := referencedName(traversal)
+		if !ok {
+			return cty.NilVal, fmt.Errorf("%w: unsupported reference %q", ErrUnresolvableValue, traversalString(traversal))
+		}
+
+		switch root {
+		case "local":
+			value, err := e.local(name, visiting)
+			if err != nil {
+				return cty.NilVal, err
+			}
+			locals[name] = value
+		case "var":
+			if _, ok := e.variables[name]; !ok {
+				if err, ok := e.invalidDefaults[name]; ok {
+					return cty.NilVal, err
+				}
+				return cty.NilVal, fmt.Errorf("%w: variable %q has no default, and isn't set in any var file", ErrUnresolvableValue, name)
+			}
+		default:
+			return cty.NilVal, fmt.Errorf("%w: unsupported reference %q", ErrUnresolvableValue, traversalString(traversal))
+		}
+	}
+
+	ctx := &hcl.EvalContext{
+		Variables: map[string]cty.Value{
+			"local": cty.ObjectVal(locals),
+			"var":   cty.ObjectVal(e.variables),
+		},
+		Functions: evalFunctions,
+	}
+
+	value, diags := expr.Value(ctx)
+	if diags.HasErrors() {
+		return cty.NilVal, fmt.Errorf("%w: %s", ErrUnresolvableValue, diags.Error())
+	}
+
+	return value, nil
+}
+
+func (e *evaluator) local(name string, visiting []string) (cty.Value, error) {
+	if value, ok := e.resolved[name]; ok {
+		return value, nil
+	}
+	if err, ok := e.failed[name]; ok {
+		return cty.NilVal, err
+	}
+
+	attr, ok := e.locals[name]
+	if !ok {
+		return cty.NilVal, fmt.Errorf("%w: local %q is not declared", ErrUnresolvableValue, name)
+	}
+
+	if slices.Contains(visiting, name) {
+		cycle := append(slices.Clone(visiting[slices.Index(visiting, name):]), name)
+		for i := range cycle {
+			cycle[i] = "local." + cycle[i]
+		}
+		return cty.NilVal, fmt.Errorf("%w: locals refer to each other in a cycle (%s)", ErrUnresolvableValue, strings.Join(cycle, " -> "))
+	}
+
+	value, err := e.value(attr.Expr, append(visiting, name))
+	if err != nil {
+		// errors caused by a cycle depend on where evaluation started, so
+		// they aren't remembered
+		if len(visiting) == 0 {
+			e.failed[name] = err
+		}
+		return cty.NilVal, err
+	}
+
+	e.resolved[name] = value
+	return value, nil
+}
+
+func referencedName(traversal hcl.Traversal) (string, bool) {
+	if len(traversal) < 2 {
+		return "", false
+	}
+
+	attr, ok := traversal[1].(hcl.TraverseAttr)
+	if !ok {
+		return "", false
+	}
+
+	return attr.Name, true
+}
+
+func traversalString(traversal hcl.Traversal) string {
+	var builder strings.Builder
+	builder.WriteString(traversal.RootName())
+	for _, step := range traversal[1:] {
+		if attr, ok := step.(hcl.TraverseAttr); ok {
+			builder.WriteString(".")
+			builder.WriteString(attr.Name)
+		}
+	}
+
+	return builder.String()
+}
+
+// newSourceEvaluator sets up an evaluator for the locals and variables declared
+// across the files of a source.
+func newSourceEvaluator(parser *hclparse.Parser, bodies []*hclsyntax.Body, varFiles []VarFile) (*evaluator, error) {
+	e := newEvaluator()
+	declaredVariables := make(map[string]hcl.Range)
+	for _, body := range bodies {
+		err := e.addLocals(body)
+		if err != nil {
+			return nil, err
+		}
+
+		err = e.addVariables(body, declaredVariables)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err := e.addVarFiles(parser, varFiles)
+	if err != nil {
+		return nil, err
+	}
+
+	return e, nil
+}
+
+// ReadVarFiles reads var files from fsys.
+func ReadVarFiles(fsys utils.FS, paths []string) ([]VarFile, error) {
+	varFiles := make([]VarFile, 0, len(paths))
+	for _, path := range paths {
+		content, err := fsys.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("%w (%q): %w", ErrCouldntReadFile, path, err)
+		}
+		varFiles = append(varFiles, VarFile{Path: path, Content: content})
+	}
+
+	return varFiles, nil
+}
@@ -0,0 +1,79 @@
+package hcl
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/dhth/tflens/internal/utils"
+)
+
+var (
+	ErrCouldntResolveSource = errors.New("couldn't resolve source")
+	ErrNoFilesFound         = errors.New("no terraform files found")
+)
+
+const tfExtension = ".tf"
+
+// ResolveFiles returns the terraform files a source path refers to. The path
+// can point to a single file, a directory (whose top-level .tf files are
+// used), or a glob pattern (which may use "**" to match nested directories).
+func ResolveFiles(fsys utils.FS, path string) ([]string, error) {
+	if utils.HasGlobMeta(path) {
+		matches, err := utils.Glob(fsys, path)
+		if err != nil {
+			return nil, fmt.Errorf("%w (%q): %w", ErrCouldntResolveSource, path, err)
+		}
+
+		var files []string
+		for _, match := range matches {
+			if !strings.HasSuffix(match, tfExtension) {
+				continue
+			}
+			info, err := fsys.Stat(match)
+			if err != nil {
+				return nil, fmt.Errorf("%w (%q): %w", ErrCouldntResolveSource, path, err)
+			}
+			if info.Mode().IsRegular() {
+				files = append(files, match)
+			}
+		}
+
+		if len(files) == 0 {
+			return nil, fmt.Errorf("%w (%q)", ErrNoFilesFound, path)
+		}
+
+		return files, nil
+	}
+
+	info, err := fsys.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("%w (%q): %w", ErrCouldntResolveSource, path, err)
+	}
+
+	if !info.IsDir() {
+		return []string{path}, nil
+	}
+
+	entries, err := fsys.ReadDir(path)
+	if err != nil {
+		return nil, fmt.Errorf("%w (%q): %w", ErrCouldntResolveSource, path, err)
+	}
+
+	var files []string
+	for _, entry := range entries {
+		if entry.Type().IsRegular() && strings.HasSuffix(entry.Name(), tfExtension) {
+			files = append(files, filepath.Join(path, entry.Name()))
+		}
+	}
+
+	if len(files) == 0 {
+		return nil, fmt.Errorf("%w (%q)", ErrNoFilesFound, path)
+	}
+
+	slices.Sort(files)
+
+	return files, nil
+}
@@ -5,57 +5,105 @@ import (
 	"fmt"
 	"regexp"
 
+	"github.com/dhth/tflens/internal/domain"
+	"github.com/dhth/tflens/internal/utils"
+	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/hclparse"
 	"github.com/hashicorp/hcl/v2/hclsyntax"
 	"github.com/zclconf/go-cty/cty"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
 )
 
 var (
+	ErrCouldntReadFile                 = errors.New("couldn't read file")
 	ErrCouldntParseFile                = errors.New("couldn't parse file")
 	ErrUnexpectedBodyType              = errors.New("unexpected body type")
 	ErrModuleMissingLabel              = errors.New("module block missing label")
+	ErrDuplicateModule                 = errors.New("module declared more than once")
 	ErrTemplateWithInterpolation       = errors.New("template expressions with interpolation are not supported")
 	ErrUnsupportedExpressionType       = errors.New("unsupported expression type")
 	ErrNullValueCannotBeConvertedToStr = errors.New("null values cannot be converted to string")
+	ErrValueRegexDidntMatch            = errors.New("value regex didn't match")
 )
 
 type TFModule struct {
 	Name      string
 	Attribute string
+	// the attribute's value before valueRegex is applied
+	RawAttribute string
+	File         string
+	// set when the attribute's value was computed from locals, variables, or
+	// function calls, rather than being a literal string, number, or bool
+	Computed bool
+	// set when the value is an object, map, list, or tuple; Attribute then
+	// holds its canonical JSON encoding
+	Complex bool
+	// set when the attribute's value couldn't be resolved; Attribute and
+	// RawAttribute are empty then
+	ResolveErr error
 }
 
-func ParseModules(path, attributeKey string, valueRegex *regexp.Regexp) ([]TFModule, error) {
+// ParseModules reads the modules declared in a source, once for every
+// attribute key; the modules for each key are returned in the same order as
+// attributeKeys. Attribute values can refer to the locals and variables
+// declared in the source; variables get their values from their defaults, and
+// from varFiles.
+func ParseModules(fsys utils.FS, path string, attributeKeys []string, valueRegex *regexp.Regexp, varFiles []VarFile) ([][]TFModule, error) {
+	attributePaths, err := parseAttributePaths(attributeKeys)
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := ResolveFiles(fsys, path)
+	if err != nil {
+		return nil, err
+	}
+
 	parser := hclparse.NewParser()
 
-	file, diags := parser.ParseHCLFile(path)
-	if diags.HasErrors() {
-		return nil, fmt.Errorf("%w (%q): %s", ErrCouldntParseFile, path, diags.Error())
+	bodies := make([]*hclsyntax.Body, 0, len(files))
+	for _, file := range files {
+		body, err := parseFile(fsys, parser, file)
+		if err != nil {
+			return nil, err
+		}
+		bodies = append(bodies, body)
 	}
 
-	body, ok := file.Body.(*hclsyntax.Body)
-	if !ok {
-		return nil, ErrUnexpectedBodyType
+	eval, err := newSourceEvaluator(parser, bodies, varFiles)
+	if err != nil {
+		return nil, err
 	}
 
-	var modules []TFModule
+	modules := make([][]TFModule, len(attributePaths))
+	declaredAt := make(map[string]hcl.Range)
+
+	for i, body := range bodies {
+		file := files[i]
+		for _, block := range body.Blocks {
+			if block.Type != "module" {
+				continue
+			}
 
-	for _, block := range body.Blocks {
-		if block.Type == "module" {
 			if len(block.Labels) == 0 {
 				return nil, fmt.Errorf("%w at %s", ErrModuleMissingLabel, block.DefRange())
 			}
 			moduleName := block.Labels[0]
 
-			if attr, exists := block.Body.Attributes[attributeKey]; exists {
-				attribute, err := extractStringValue(attr.Expr)
-				if err != nil {
-					return nil, fmt.Errorf("couldn't extract %s from module %q: %w", attributeKey, moduleName, err)
+			if previous, ok := declaredAt[moduleName]; ok {
+				return nil, fmt.Errorf("%w: %q is declared at %s and %s", ErrDuplicateModule, moduleName, previous, block.DefRange())
+			}
+			declaredAt[moduleName] = block.DefRange()
+
+			for k, attributePath := range attributePaths {
+				attr, exists := block.Body.Attributes[attributePath.Name]
+				if !exists {
+					continue
+				}
+
+				if module, ok := newTFModule(moduleName, file, attr.Expr, attributePath, eval, valueRegex); ok {
+					modules[k] = append(modules[k], module)
 				}
-				attribute = extractValue(attribute, valueRegex)
-				modules = append(modules, TFModule{
-					Name:      moduleName,
-					Attribute: attribute,
-				})
 			}
 		}
 	}
@@ -63,6 +111,104 @@ func ParseModules(path, attributeKey string, valueRegex *regexp.Regexp) ([]TFMod
 	return modules, nil
 }
 
+func parseAttributePaths(attributeKeys []string) ([]domain.AttributePath, error) {
+	attributePaths := make([]domain.AttributePath, len(attributeKeys))
+	for k, attributeKey := range attributeKeys {
+		attributePath, err := domain.ParseAttributePath(attributeKey)
+		if err != nil {
+			return nil, err
+		}
+		attributePaths[k] = attributePath
+	}
+
+	return attributePaths, nil
+}
+
+// newTFModule reads the value an attribute path points to in a module. Literal
+// values are used as is; anything else is evaluated, and a failure to do so is
+// recorded on the module. False is returned if the path doesn't exist in the
+// attribute's value.
+func newTFModule(name, file string, expr hclsyntax.Expression, path domain.AttributePath, eval *evaluator, valueRegex *regexp.Regexp) (TFModule, bool) {
+	module := TFModule{
+		Name: name,
+		File: file,
+	}
+
+	if !path.IsNested() {
+		if attribute, err := extractStringValue(expr); err == nil {
+			module.Attribute = extractValue(attribute, valueRegex)
+			module.RawAttribute = attribute
+			return module, true
+		}
+	}
+
+	value, err := eval.value(expr, nil)
+	if err != nil {
+		module.ResolveErr = err
+		return module, true
+	}
+	module.Computed = true
+
+	value, ok := lookupPath(path, value)
+	if !ok {
+		return module, false
+	}
+
+	if !value.IsWhollyKnown() {
+		module.ResolveErr = fmt.Errorf("%w: value is not known", ErrUnresolvableValue)
+		return module, true
+	}
+
+	if isComplexType(value.Type()) {
+		encoded, err := ctyjson.Marshal(value, value.Type())
+		if err != nil {
+			module.ResolveErr = fmt.Errorf("%w: %w", ErrUnresolvableValue, err)
+			return module, true
+		}
+		module.Attribute = string(encoded)
+		module.RawAttribute = string(encoded)
+		module.Complex = true
+		return module, true
+	}
+
+	attribute, err := convertToString(value)
+	if err != nil {
+		module.ResolveErr = fmt.Errorf("%w: %w", ErrUnresolvableValue, err)
+		return module, true
+	}
+
+	module.Attribute = extractValue(attribute, valueRegex)
+	module.RawAttribute = attribute
+
+	return module, true
+}
+
+// isComplexType reports whether values of a type are compared by their JSON
+// encoding; cty encodes the attributes of objects and the keys of maps in
+// sorted order, which makes the encoding canonical.
+func isComplexType(ty cty.Type) bool {
+	return ty.IsObjectType() || ty.IsMapType() || ty.IsListType() || ty.IsTupleType() || ty.IsSetType()
+}
+
+func parseFile(fsys utils.FS, parser *hclparse.Parser, path string) (*hclsyntax.Body, error) {
+	content, err := fsys.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("%w (%q): %w", ErrCouldntReadFile, path, err)
+	}
+
+	file, diags := parser.ParseHCL(content, path)
+	if diags.HasErrors() {
+		return nil, fmt.Errorf("%w (%q): %s", ErrCouldntParseFile, path, diags.Error())
+	}
+
+	body, ok := file.Body.(*hclsyntax.Body)
+	if !ok {
+		return nil, ErrUnexpectedBodyType
+	}
+
+	return body, nil
+}
+
 func extractStringValue(expr hclsyntax.Expression) (string, error) {
 	switch e := expr.(type) {
 	case *hclsyntax.TemplateExpr:
@@ -120,3 +266,19 @@ func extractValue(value string, valueRegex *regexp.Regexp) string {
 
 	return value
 }
+
+// ReplaceValue is the inverse of extractValue; it replaces the portion of raw
+// that valueRegex would extract with value. An error is returned if
+// valueRegex doesn't match raw, since there's no portion to replace then.
+func ReplaceValue(raw, value string, valueRegex *regexp.Regexp) (string, error) {
+	if valueRegex == nil {
+		return value, nil
+	}
+
+	loc := valueRegex.FindStringSubmatchIndex(raw)
+	if len(loc) < 4 || loc[2] < 0 {
+		return "", fmt.Errorf("%w: %q doesn't match %q", ErrValueRegexDidntMatch, valueRegex.String(), raw)
+	}
+
+	return raw[:loc[2]] + value + raw[loc[3]:], nil
+}
@@ -0,0 +1,43 @@
+package hcl
+
+import (
+	"strconv"
+
+	"github.com/dhth/tflens/internal/domain"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// lookupPath returns the value the path's steps point to in an attribute's
+// value; false is returned if it isn't present.
+func lookupPath(path domain.AttributePath, value cty.Value) (cty.Value, bool) {
+	for _, step := range path.Steps {
+		if value.IsNull() || !value.IsKnown() {
+			return cty.NilVal, false
+		}
+
+		ty := value.Type()
+		switch {
+		case ty.IsObjectType():
+			if !ty.HasAttribute(step) {
+				return cty.NilVal, false
+			}
+			value = value.GetAttr(step)
+		case ty.IsMapType():
+			key := cty.StringVal(step)
+			if !value.HasIndex(key).True() {
+				return cty.NilVal, false
+			}
+			value = value.Index(key)
+		case ty.IsListType() || ty.IsTupleType():
+			index, err := strconv.Atoi(step)
+			if err != nil || index < 0 || index >= value.LengthInt() {
+				return cty.NilVal, false
+			}
+			value = value.Index(cty.NumberIntVal(int64(index)))
+		default:
+			return cty.NilVal, false
+		}
+	}
+
+	return value, true
+}
@@ -0,0 +1,149 @@
+package hcl
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/dhth/tflens/internal/utils"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+)
+
+var (
+	ErrDuplicateProvider          = errors.New("provider requirement declared more than once")
+	ErrInvalidProviderRequirement = errors.New("invalid provider requirement")
+	ErrInvalidRequiredVersion     = errors.New("invalid required_version")
+)
+
+// RequiredVersionName is the name a source's required_version is reported
+// under, alongside its providers.
+const RequiredVersionName = "terraform"
+
+// TFRequirement is a version constraint declared in a terraform block.
+type TFRequirement struct {
+	// the provider's local name, or RequiredVersionName
+	Name       string
+	Constraint string
+	File       string
+}
+
+// ParseRequirements reads the version constraints of the providers declared in
+// the required_providers blocks of a source, along with its required_version.
+// Providers without a version constraint are skipped. required_version can be
+// set in several terraform blocks, all of which apply; their constraints are
+// joined.
+func ParseRequirements(fsys utils.FS, path string) ([]TFRequirement, error) {
+	files, err := ResolveFiles(fsys, path)
+	if err != nil {
+		return nil, err
+	}
+
+	parser := hclparse.NewParser()
+
+	var requirements []TFRequirement
+	declaredAt := make(map[string]hcl.Range)
+
+	var requiredVersions []string
+	var requiredVersionFile string
+
+	for _, file := range files {
+		body, err := parseFile(fsys, parser, file)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, block := range body.Blocks {
+			if block.Type != "terraform" {
+				continue
+			}
+
+			if attr, ok := block.Body.Attributes["required_version"]; ok {
+				constraint, err := extractStringValue(attr.Expr)
+				if err != nil {
+					return nil, fmt.Errorf("%w at %s: %w", ErrInvalidRequiredVersion, attr.SrcRange, err)
+				}
+
+				requiredVersions = append(requiredVersions, strings.TrimSpace(constraint))
+				if requiredVersionFile == "" {
+					requiredVersionFile = file
+				}
+			}
+
+			for _, providersBlock := range block.Body.Blocks {
+				if providersBlock.Type != "required_providers" {
+					continue
+				}
+
+				for name, attr := range providersBlock.Body.Attributes {
+					if previous, ok := declaredAt[name]; ok {
+						return nil, fmt.Errorf("%w: %q is declared at %s and %s", ErrDuplicateProvider, name, previous, attr.SrcRange)
+					}
+					declaredAt[name] = attr.SrcRange
+
+					constraint, ok, err := providerConstraint(attr.Expr)
+					if err != nil {
+						return nil, fmt.Errorf("%w %q at %s: %w", ErrInvalidProviderRequirement, name, attr.SrcRange, err)
+					}
+					if !ok {
+						continue
+					}
+
+					requirements = append(requirements, TFRequirement{
+						Name:       name,
+						Constraint: constraint,
+						File:       file,
+					})
+				}
+			}
+		}
+	}
+
+	if len(requiredVersions) > 0 {
+		requirements = append(requirements, TFRequirement{
+			Name:       RequiredVersionName,
+			Constraint: strings.Join(requiredVersions, ", "),
+			File:       requiredVersionFile,
+		})
+	}
+
+	return requirements, nil
+}
+
+// providerConstraint returns the version constraint of a provider requirement,
+// which is either an object with a "version" attribute, or (in the legacy
+// form) the constraint itself. False is returned if there's no constraint.
+// Only the "version" attribute of an object is evaluated, since others, like
+// configuration_aliases, refer to providers, and can't be evaluated.
+func providerConstraint(expr hclsyntax.Expression) (string, bool, error) {
+	object, ok := expr.(*hclsyntax.ObjectConsExpr)
+	if !ok {
+		constraint, err := extractStringValue(expr)
+		if err != nil {
+			return "", false, err
+		}
+
+		return strings.TrimSpace(constraint), true, nil
+	}
+
+	for _, item := range object.Items {
+		key, diags := item.KeyExpr.Value(nil)
+		if diags.HasErrors() || key.IsNull() || key.Type() != cty.String {
+			continue
+		}
+		if key.AsString() != "version" {
+			continue
+		}
+
+		constraint, err := extractStringValue(item.ValueExpr)
+		if err != nil {
+			return "", false, fmt.Errorf("version must be a string: %w", err)
+		}
+
+		return strings.TrimSpace(constraint), true, nil
+	}
+
+	return "", false, nil
+}
@@ -0,0 +1,133 @@
+package hcl
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+)
+
+var (
+	ErrCouldntRewriteFile = errors.New("couldn't rewrite file")
+	ErrBlockNotFound      = errors.New("block not found")
+	ErrAttributeNotFound  = errors.New("attribute not found")
+)
+
+// RewriteModuleAttributes sets the attribute for the given modules (module name
+// -> new value) in the contents of a terraform file. Only the bytes of the
+// attributes' expressions are replaced; everything else in the file, including
+// formatting and comments, is left untouched.
+func RewriteModuleAttributes(path string, content []byte, attributeKey string, values map[string]string) ([]byte, error) {
+	body, err := parseContent(path, content)
+	if err != nil {
+		return nil, err
+	}
+
+	var replacements []replacement
+	for moduleName, value := range values {
+		block := findBlock(body, "module", moduleName)
+		if block == nil {
+			return nil, fmt.Errorf("%w (%q): %w: module %q", ErrCouldntRewriteFile, path, ErrBlockNotFound, moduleName)
+		}
+
+		r, err := newReplacement(block.Body, attributeKey, value)
+		if err != nil {
+			return nil, fmt.Errorf("%w (%q): module %q: %w", ErrCouldntRewriteFile, path, moduleName, err)
+		}
+		replacements = append(replacements, r)
+	}
+
+	return applyReplacements(content, replacements), nil
+}
+
+// RewriteTerragruntAttribute sets the attribute in the terraform block of a
+// terragrunt unit's configuration.
+func RewriteTerragruntAttribute(path string, content []byte, attributeKey, value string) ([]byte, error) {
+	body, err := parseContent(path, content)
+	if err != nil {
+		return nil, err
+	}
+
+	block := findBlock(body, "terraform", "")
+	if block == nil {
+		return nil, fmt.Errorf("%w (%q): %w: terraform", ErrCouldntRewriteFile, path, ErrBlockNotFound)
+	}
+
+	r, err := newReplacement(block.Body, attributeKey, value)
+	if err != nil {
+		return nil, fmt.Errorf("%w (%q): %w", ErrCouldntRewriteFile, path, err)
+	}
+
+	return applyReplacements(content, []replacement{r}), nil
+}
+
+type replacement struct {
+	start int
+	end   int
+	value []byte
+}
+
+func parseContent(path string, content []byte) (*hclsyntax.Body, error) {
+	file, diags := hclsyntax.ParseConfig(content, path, hcl.InitialPos)
+	if diags.HasErrors() {
+		return nil, fmt.Errorf("%w (%q): %s", ErrCouldntParseFile, path, diags.Error())
+	}
+
+	body, ok := file.Body.(*hclsyntax.Body)
+	if !ok {
+		return nil, ErrUnexpectedBodyType
+	}
+
+	return body, nil
+}
+
+func findBlock(body *hclsyntax.Body, blockType, label string) *hclsyntax.Block {
+	for _, block := range body.Blocks {
+		if block.Type != blockType {
+			continue
+		}
+
+		if label == "" || (len(block.Labels) > 0 && block.Labels[0] == label) {
+			return block
+		}
+	}
+
+	return nil
+}
+
+func newReplacement(body *hclsyntax.Body, attributeKey, value string) (replacement, error) {
+	attr, ok := body.Attributes[attributeKey]
+	if !ok {
+		return replacement{}, fmt.Errorf("%w: %s", ErrAttributeNotFound, attributeKey)
+	}
+
+	exprRange := attr.Expr.Range()
+
+	return replacement{
+		start: exprRange.Start.Byte,
+		end:   exprRange.End.Byte,
+		value: hclwrite.TokensForValue(cty.StringVal(value)).Bytes(),
+	}, nil
+}
+
+func applyReplacements(content []byte, replacements []replacement) []byte {
+	sort.Slice(replacements, func(i, j int) bool {
+		return replacements[i].start > replacements[j].start
+	})
+
+	result := make([]byte, len(content))
+	copy(result, content)
+	for _, r := range replacements {
+		updated := make([]byte, 0, len(result)-(r.end-r.start)+len(r.value))
+		updated = append(updated, result[:r.start]...)
+		updated = append(updated, r.value...)
+		updated = append(updated, result[r.end:]...)
+		result = updated
+	}
+
+	return result
+}
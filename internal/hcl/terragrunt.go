@@ -0,0 +1,107 @@
+package hcl
+
+import (
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/dhth/tflens/internal/utils"
+	"github.com/hashicorp/hcl/v2/hclparse"
+)
+
+const terragruntFileName = "terragrunt.hcl"
+
+// ParseTerragruntUnits walks a terragrunt tree, and treats every directory
+// containing a terragrunt.hcl file as a "module". The unit's path relative to
+// root is used as its name, and attributes are read from the unit's terraform
+// block. As with ParseModules, units are returned for every attribute key.
+func ParseTerragruntUnits(fsys utils.FS, root string, attributeKeys []string, valueRegex *regexp.Regexp) ([][]TFModule, error) {
+	attributePaths, err := parseAttributePaths(attributeKeys)
+	if err != nil {
+		return nil, err
+	}
+
+	unitFiles, err := findTerragruntFiles(fsys, root)
+	if err != nil {
+		return nil, err
+	}
+
+	parser := hclparse.NewParser()
+
+	modules := make([][]TFModule, len(attributePaths))
+	for _, unitFile := range unitFiles {
+		body, err := parseFile(fsys, parser, unitFile)
+		if err != nil {
+			return nil, err
+		}
+
+		unitDir, err := filepath.Rel(root, filepath.Dir(unitFile))
+		if err != nil {
+			return nil, fmt.Errorf("%w (%q): %w", ErrCouldntResolveSource, unitFile, err)
+		}
+		unitName := filepath.ToSlash(unitDir)
+
+		// a unit's attribute can refer to the unit's own locals
+		eval := newEvaluator()
+		err = eval.addLocals(body)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, block := range body.Blocks {
+			if block.Type != "terraform" {
+				continue
+			}
+
+			for k, attributePath := range attributePaths {
+				attr, exists := block.Body.Attributes[attributePath.Name]
+				if !exists {
+					continue
+				}
+
+				if module, ok := newTFModule(unitName, unitFile, attr.Expr, attributePath, eval, valueRegex); ok {
+					modules[k] = append(modules[k], module)
+				}
+			}
+		}
+	}
+
+	return modules, nil
+}
+
+func findTerragruntFiles(fsys utils.FS, root string) ([]string, error) {
+	var files []string
+	err := fs.WalkDir(fsys, root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() {
+			// skips directories like .terragrunt-cache and .git
+			if path != root && strings.HasPrefix(d.Name(), ".") {
+				return fs.SkipDir
+			}
+			return nil
+		}
+
+		if d.Name() == terragruntFileName {
+			files = append(files, filepath.FromSlash(path))
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w (%q): %w", ErrCouldntResolveSource, root, err)
+	}
+
+	if len(files) == 0 {
+		return nil, fmt.Errorf("%w (%q): no %s files found", ErrCouldntResolveSource, root, terragruntFileName)
+	}
+
+	slices.Sort(files)
+
+	return files, nil
+}
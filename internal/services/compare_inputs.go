@@ -0,0 +1,137 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+
+	"github.com/dhth/tflens/internal/domain"
+	"github.com/dhth/tflens/internal/hcl"
+)
+
+var ErrInputsUnsupportedForTerragrunt = errors.New("comparing module inputs is not supported for terragrunt sources")
+
+// GetInputsResult compares the arguments set in the module blocks of a
+// comparison's sources. Only modules present in two or more sources are
+// included.
+func GetInputsResult(ctx context.Context, comparison domain.Comparison, jobs int) (domain.InputsResult, error) {
+	var zero domain.InputsResult
+	sourceLabels := make([]string, len(comparison.Sources))
+	for i, source := range comparison.Sources {
+		if source.Kind == domain.TerragruntSource {
+			return zero, fmt.Errorf("%w (source %q)", ErrInputsUnsupportedForTerragrunt, source.Label)
+		}
+		sourceLabels[i] = source.Label
+	}
+
+	parsedSources := make([][]hcl.TFModuleArguments, len(comparison.Sources))
+	err := runConcurrently(len(comparison.Sources), jobs, func(i int) error {
+		source := comparison.Sources[i]
+		fsys, err := sourceFS(ctx, source)
+		if err != nil {
+			return err
+		}
+
+		modules, err := hcl.ParseModuleArguments(fsys, source.Path)
+		if err != nil {
+			return err
+		}
+
+		parsedSources[i] = modules
+		return nil
+	})
+	if err != nil {
+		return zero, err
+	}
+
+	//        module     label  arguments
+	store := make(map[string]map[string]map[string]string)
+	for i, source := range comparison.Sources {
+		for _, mod := range parsedSources[i] {
+			if slices.Contains(comparison.IgnoreModules, mod.Name) {
+				continue
+			}
+
+			if _, ok := store[mod.Name]; !ok {
+				store[mod.Name] = make(map[string]map[string]string)
+			}
+			store[mod.Name][source.Label] = mod.Arguments
+		}
+	}
+
+	result := buildInputsResult(store, sourceLabels)
+	result.Name = comparison.Name
+
+	return result, nil
+}
+
+func buildInputsResult(store map[string]map[string]map[string]string, sourceLabels []string) domain.InputsResult {
+	modules := make([]string, 0, len(store))
+	for module, labels := range store {
+		if len(labels) < 2 {
+			continue
+		}
+		modules = append(modules, module)
+	}
+	sort.Strings(modules)
+
+	result := domain.InputsResult{
+		SourceLabels: sourceLabels,
+		Modules:      make([]domain.ModuleInputs, 0, len(modules)),
+	}
+
+	for _, module := range modules {
+		moduleInputs := domain.ModuleInputs{
+			Name: module,
+		}
+
+		argumentSet := make(map[string]struct{})
+		for _, label := range sourceLabels {
+			arguments, ok := store[module][label]
+			if !ok {
+				continue
+			}
+
+			moduleInputs.Labels = append(moduleInputs.Labels, label)
+			for name := range arguments {
+				argumentSet[name] = struct{}{}
+			}
+		}
+
+		arguments := make([]string, 0, len(argumentSet))
+		for name := range argumentSet {
+			arguments = append(arguments, name)
+		}
+		sort.Strings(arguments)
+
+		for _, name := range arguments {
+			argument := domain.ModuleArgument{
+				Name:   name,
+				Values: make(map[string]string),
+			}
+
+			for _, label := range moduleInputs.Labels {
+				value, ok := store[module][label][name]
+				if !ok {
+					argument.Status = domain.ArgumentMissing
+					continue
+				}
+
+				for _, other := range argument.Values {
+					if other != value && argument.Status == domain.ArgumentSame {
+						argument.Status = domain.ArgumentDiffers
+					}
+				}
+				argument.Values[label] = value
+			}
+
+			moduleInputs.Arguments = append(moduleInputs.Arguments, argument)
+		}
+
+		result.Modules = append(result.Modules, moduleInputs)
+	}
+
+	return result
+}
@@ -0,0 +1,124 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/dhth/tflens/internal/domain"
+	"github.com/dhth/tflens/internal/hcl"
+	"github.com/gkampitakis/go-snaps/snaps"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetInputsResult(t *testing.T) {
+	//-------------//
+	//  SUCCESSES  //
+	//-------------//
+
+	t.Run("works for various cases", func(t *testing.T) {
+		// GIVEN
+		comparison := domain.Comparison{
+			Name:          "test-comparison",
+			AttributeKeys: []string{"source"},
+			Sources: []domain.Source{
+				{
+					Path:  "testdata/inputs/dev/main.tf",
+					Label: "dev",
+				},
+				{
+					Path:  "testdata/inputs/qa/main.tf",
+					Label: "qa",
+				},
+				{
+					Path:  "testdata/inputs/prod/main.tf",
+					Label: "prod",
+				},
+			},
+		}
+
+		// WHEN
+		result, err := GetInputsResult(t.Context(), comparison, 1)
+
+		// THEN
+		require.NoError(t, err)
+		snaps.MatchYAML(t, result)
+	})
+
+	t.Run("skips ignored modules", func(t *testing.T) {
+		// GIVEN
+		comparison := domain.Comparison{
+			Name:          "test-comparison",
+			AttributeKeys: []string{"source"},
+			IgnoreModules: []string{"module_a", "module_d"},
+			Sources: []domain.Source{
+				{
+					Path:  "testdata/inputs/dev/main.tf",
+					Label: "dev",
+				},
+				{
+					Path:  "testdata/inputs/prod/main.tf",
+					Label: "prod",
+				},
+			},
+		}
+
+		// WHEN
+		result, err := GetInputsResult(t.Context(), comparison, 1)
+
+		// THEN
+		require.NoError(t, err)
+		snaps.MatchYAML(t, result)
+	})
+
+	//------------//
+	//  FAILURES  //
+	//------------//
+
+	t.Run("fails for terragrunt sources", func(t *testing.T) {
+		// GIVEN
+		comparison := domain.Comparison{
+			Name:          "test-comparison",
+			AttributeKeys: []string{"source"},
+			Sources: []domain.Source{
+				{
+					Path:  "testdata/terragrunt/qa",
+					Label: "qa",
+					Kind:  domain.TerragruntSource,
+				},
+				{
+					Path:  "testdata/inputs/prod/main.tf",
+					Label: "prod",
+				},
+			},
+		}
+
+		// WHEN
+		_, err := GetInputsResult(t.Context(), comparison, 1)
+
+		// THEN
+		require.ErrorIs(t, err, ErrInputsUnsupportedForTerragrunt)
+	})
+
+	t.Run("fails when a module is declared more than once", func(t *testing.T) {
+		// GIVEN
+		comparison := domain.Comparison{
+			Name:          "test-comparison",
+			AttributeKeys: []string{"source"},
+			Sources: []domain.Source{
+				{
+					Path:  "testdata/duplicates",
+					Label: "dev",
+				},
+				{
+					Path:  "testdata/inputs/prod/main.tf",
+					Label: "prod",
+				},
+			},
+		}
+
+		// WHEN
+		_, err := GetInputsResult(t.Context(), comparison, 1)
+
+		// THEN
+		require.ErrorIs(t, err, hcl.ErrDuplicateModule)
+	})
+}
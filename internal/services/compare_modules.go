@@ -2,6 +2,7 @@ package services
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"os"
@@ -9,136 +10,572 @@ import (
 	"regexp"
 	"slices"
 	"sort"
+	"strings"
+	"time"
 
+	"github.com/dhth/tflens/internal/cache"
 	"github.com/dhth/tflens/internal/domain"
+	"github.com/dhth/tflens/internal/git"
 	"github.com/dhth/tflens/internal/hcl"
+	"github.com/dhth/tflens/internal/utils"
 )
 
-var ErrCouldntComputeDiff = errors.New("couldn't compute diff")
+var (
+	ErrCouldntComputeDiff      = errors.New("couldn't compute diff")
+	ErrCouldntComputeCommitLog = errors.New("couldn't compute commit log")
+	ErrDiffTimedOut            = errors.New("diff command timed out")
+	ErrCouldntDetermineWorkDir = errors.New("couldn't determine working directory")
+)
+
+// diffWaitDelay bounds how long to wait for a diff command's output pipes to be
+// closed after it has been killed (eg. when a process it spawned is still
+// holding them).
+const diffWaitDelay = 5 * time.Second
+
+// maxDiffErrorStderrLines is the number of trailing lines of a failed diff
+// command's stderr that are recorded on its module.
+const maxDiffErrorStderrLines = 20
+
+type ComparisonOptions struct {
+	GlobalValueRegex     *regexp.Regexp
+	IgnoreMissingModules bool
+	IncludeDiffs         bool
+	// maximum number of sources parsed, or diff commands run, at a time
+	Jobs int
+	// overrides the timeout in the comparison's diff config, if non-zero
+	DiffTimeout time.Duration
+	// record diff failures on their modules instead of returning an error
+	ContinueOnDiffErrors bool
+	// stores the output of diff and log commands across runs; nil disables
+	// caching
+	DiffCache *cache.Cache
+	// called with problems that don't fail the comparison, like output that
+	// couldn't be cached; calls can be concurrent, and nil ignores them
+	Warn func(error)
+}
 
-func GetComparisonResult(
-	comparison domain.Comparison,
-	globalValueRegex *regexp.Regexp,
-	ignoreMissingModules, includeDiffs bool,
-) (domain.ComparisonResult, error) {
+func GetComparisonResult(ctx context.Context, comparison domain.Comparison, opts ComparisonOptions) (domain.ComparisonResult, error) {
 	var zero domain.ComparisonResult
 	sourceLabels := make([]string, len(comparison.Sources))
 	for i, source := range comparison.Sources {
 		sourceLabels[i] = source.Label
 	}
 
-	valueRegex := globalValueRegex
-	if comparison.ValueRegex != nil {
-		valueRegex = comparison.ValueRegex
+	//                   source  attribute
+	parsedSources := make([][][]hcl.TFModule, len(comparison.Sources))
+	err := runConcurrently(len(comparison.Sources), opts.Jobs, func(i int) error {
+		source := comparison.Sources[i]
+		valueRegex := sourceValueRegex(comparison, source, opts.GlobalValueRegex)
+		modules, err := parseSource(ctx, source, comparison.AttributeKeys, valueRegex)
+		if err != nil {
+			return err
+		}
+
+		parsedSources[i] = modules
+		return nil
+	})
+	if err != nil {
+		return zero, err
+	}
+
+	attributes := make([]attributeStore, len(comparison.AttributeKeys))
+	for k, attributeKey := range comparison.AttributeKeys {
+		attributes[k] = newAttributeStore(attributeKey)
+		for i, source := range comparison.Sources {
+			for _, mod := range parsedSources[i][k] {
+				if slices.Contains(comparison.IgnoreModules, mod.Name) {
+					continue
+				}
+
+				attributes[k].add(mod, source.Label)
+			}
+		}
 	}
 
-	//                module     label  attribute
-	store := make(map[string]map[string]string)
+	result := buildComparisonResult(attributes, sourceLabels, opts.IgnoreMissingModules, comparison.SemverCfg, comparison.PromotionOrder)
+	result.Name = comparison.Name
 
-	for _, source := range comparison.Sources {
-		result, err := hcl.ParseModules(source.Path, comparison.AttributeKey, valueRegex)
+	if opts.IncludeDiffs && len(comparison.DiffCfgs) > 0 {
+		diffCfgs := slices.Clone(comparison.DiffCfgs)
+		if opts.DiffTimeout > 0 {
+			for i := range diffCfgs {
+				diffCfgs[i].Timeout = opts.DiffTimeout
+			}
+		}
+
+		caching := newDiffCaching(opts.DiffCache, opts.Warn)
+		err := addDiffs(ctx, result.Modules, diffCfgs, opts.Jobs, opts.ContinueOnDiffErrors, caching)
 		if err != nil {
 			return zero, err
 		}
+	}
 
-		for _, mod := range result {
-			if slices.Contains(comparison.IgnoreModules, mod.Name) {
-				continue
-			}
+	return result, nil
+}
 
-			labelAttributeMap, ok := store[mod.Name]
-			if !ok {
-				labelAttributeMap = make(map[string]string)
-			}
+// sourceValueRegex returns the regex to be used for a source; a source's regex
+// takes precedence over the comparison's, which takes precedence over the
+// global one.
+func sourceValueRegex(comparison domain.Comparison, source domain.Source, globalValueRegex *regexp.Regexp) *regexp.Regexp {
+	if source.ValueRegex != nil {
+		return source.ValueRegex
+	}
+
+	if comparison.ValueRegex != nil {
+		return comparison.ValueRegex
+	}
+
+	return globalValueRegex
+}
 
-			labelAttributeMap[source.Label] = mod.Attribute
-			store[mod.Name] = labelAttributeMap
+// parseSource reads a source's modules for every attribute key, in the same
+// order as attributeKeys.
+func parseSource(ctx context.Context, source domain.Source, attributeKeys []string, valueRegex *regexp.Regexp) ([][]hcl.TFModule, error) {
+	fsys, err := sourceFS(ctx, source)
+	if err != nil {
+		return nil, err
+	}
+
+	switch source.Kind {
+	case domain.TerragruntSource:
+		return hcl.ParseTerragruntUnits(fsys, source.Path, attributeKeys, valueRegex)
+	default:
+		varFiles, err := readVarFiles(ctx, source)
+		if err != nil {
+			return nil, err
 		}
+
+		return hcl.ParseModules(fsys, source.Path, attributeKeys, valueRegex, varFiles)
+	}
+}
+
+// sourceFS returns the file system a source is to be read from: the working
+// tree, or the source's ref, if it has one.
+func sourceFS(ctx context.Context, source domain.Source) (utils.FS, error) {
+	if source.Ref == "" {
+		return utils.OSFS{}, nil
 	}
 
-	var diffCfg *domain.DiffConfig
-	if includeDiffs {
-		diffCfg = comparison.DiffCfg
+	root := source.Path
+	if utils.HasGlobMeta(root) {
+		root = utils.GlobBase(root)
 	}
-	result, err := buildComparisonResult(store, sourceLabels, ignoreMissingModules, diffCfg)
+
+	gitFS, err := git.NewFS(ctx, source.Ref, root)
 	if err != nil {
-		return zero, err
+		return nil, err
 	}
 
-	return result, nil
+	return gitFS, nil
 }
 
+// readVarFiles reads a source's var files from the working tree, or from the
+// source's ref, if it has one.
+func readVarFiles(ctx context.Context, source domain.Source) ([]hcl.VarFile, error) {
+	if source.Ref == "" {
+		return hcl.ReadVarFiles(utils.OSFS{}, source.VarFiles)
+	}
+
+	varFiles := make([]hcl.VarFile, 0, len(source.VarFiles))
+	for _, path := range source.VarFiles {
+		gitFS, err := git.NewFS(ctx, source.Ref, path)
+		if err != nil {
+			return nil, err
+		}
+
+		files, err := hcl.ReadVarFiles(gitFS, []string{path})
+		if err != nil {
+			return nil, err
+		}
+		varFiles = append(varFiles, files...)
+	}
+
+	return varFiles, nil
+}
+
+// attributeStore holds the values of an attribute for every module, along with
+// the reasons why some of them couldn't be resolved.
+type attributeStore struct {
+	key string
+	//      module     label  value
+	values map[string]map[string]string
+	//          module     label  reason
+	unresolved map[string]map[string]string
+	//       module     label  whether the value is complex
+	complex map[string]map[string]bool
+}
+
+func newAttributeStore(key string) attributeStore {
+	return attributeStore{
+		key:        key,
+		values:     make(map[string]map[string]string),
+		unresolved: make(map[string]map[string]string),
+		complex:    make(map[string]map[string]bool),
+	}
+}
+
+func (s attributeStore) add(mod hcl.TFModule, label string) {
+	if mod.ResolveErr != nil {
+		if _, ok := s.unresolved[mod.Name]; !ok {
+			s.unresolved[mod.Name] = make(map[string]string)
+		}
+		s.unresolved[mod.Name][label] = mod.ResolveErr.Error()
+		return
+	}
+
+	if _, ok := s.values[mod.Name]; !ok {
+		s.values[mod.Name] = make(map[string]string)
+	}
+	s.values[mod.Name][label] = mod.Attribute
+
+	if mod.Complex {
+		if _, ok := s.complex[mod.Name]; !ok {
+			s.complex[mod.Name] = make(map[string]bool)
+		}
+		s.complex[mod.Name][label] = true
+	}
+}
+
+// buildComparisonResult determines the status of every module across all
+// attributes; the first attribute is the primary one, whose values are stored
+// in each module's Values. A module is out of sync if any of its attributes
+// are, and its drift is the most severe one among such attributes. Complex
+// values of such attributes are diffed.
 func buildComparisonResult(
-	store map[string]map[string]string,
+	attributes []attributeStore,
 	sourceLabels []string,
 	ignoreMissingModules bool,
-	diffCfg *domain.DiffConfig,
-) (domain.ComparisonResult, error) {
-	modules := make([]string, 0, len(store))
-	for k := range store {
+	semverCfg *domain.SemverConfig,
+	promotionOrder []string,
+) domain.ComparisonResult {
+	moduleSet := make(map[string]struct{})
+	for _, attribute := range attributes {
+		for module := range attribute.values {
+			moduleSet[module] = struct{}{}
+		}
+		for module := range attribute.unresolved {
+			moduleSet[module] = struct{}{}
+		}
+	}
+
+	modules := make([]string, 0, len(moduleSet))
+	for k := range moduleSet {
 		modules = append(modules, k)
 	}
 	sort.Strings(modules)
 
+	var attributeKeys []string
+	if len(attributes) > 1 {
+		for _, attribute := range attributes {
+			attributeKeys = append(attributeKeys, attribute.key)
+		}
+	}
+
 	moduleResults := make([]domain.ModuleResult, 0, len(modules))
 	for _, moduleName := range modules {
-		labelToAttr := store[moduleName]
-
-		//                 label  attribute
-		values := make(map[string]string)
-
-		isMissing := false
+		// a module is present in a source if any of its attributes are set
+		// there
+		present := 0
 		for _, label := range sourceLabels {
-			value, exists := labelToAttr[label]
-			if exists {
-				values[label] = value
-			} else {
-				isMissing = true
+			for _, attribute := range attributes {
+				_, hasValue := attribute.values[moduleName][label]
+				_, isUnresolved := attribute.unresolved[moduleName][label]
+				if hasValue || isUnresolved {
+					present++
+					break
+				}
 			}
 		}
+		isMissing := present < len(sourceLabels)
+
+		var statuses []domain.ModuleStatus
+		var drift domain.Drift
+		var valueDiffs []domain.ValueDiff
+		var attributeValues map[string]domain.AttributeValues
+		if len(attributes) > 1 {
+			attributeValues = make(map[string]domain.AttributeValues, len(attributes))
+		}
+		for _, attribute := range attributes {
+			//                 label  attribute
+			values := make(map[string]string)
+			for _, label := range sourceLabels {
+				if value, exists := attribute.values[moduleName][label]; exists {
+					values[label] = value
+				}
+			}
+			unresolved := attribute.unresolved[moduleName]
 
-		status := determineModuleStatus(values, isMissing, ignoreMissingModules)
+			if attributeValues != nil {
+				attributeValues[attribute.key] = domain.AttributeValues{
+					Values:     values,
+					Unresolved: unresolved,
+				}
+			}
 
-		var diffResult *domain.DiffResult
-		if status == domain.StatusOutOfSync && diffCfg != nil {
-			baseRef, baseExists := values[diffCfg.BaseLabel]
-			headRef, headExists := values[diffCfg.HeadLabel]
+			set := len(values) + len(unresolved)
+			if set == 0 {
+				// the attribute isn't used by the module
+				continue
+			}
 
-			if baseExists && headExists && (baseRef != headRef) {
-				diffOutput, diffErr := generateDiff(
-					moduleName,
-					baseRef,
-					headRef,
-					diffCfg.Cmd,
-				)
-				if diffErr != nil {
-					return domain.ComparisonResult{}, fmt.Errorf("%w for module %q (command: %v): %w", ErrCouldntComputeDiff, moduleName, diffCfg.Cmd, diffErr)
+			status := determineModuleStatus(values, isMissing, ignoreMissingModules)
+			// a module can't be known to be in sync if any of its values
+			// couldn't be resolved, or if an attribute is only set in some of
+			// the sources it's present in
+			if (len(unresolved) > 0 || set < present) && (status == domain.StatusInSync || status == domain.StatusNotApplicable) {
+				status = domain.StatusOutOfSync
+			}
+			if status == domain.StatusOutOfSync && isAheadOfUpstream(values, promotionOrder) {
+				status = domain.StatusAheadOfUpstream
+			}
+			if isOutOfSync(status) && semverCfg != nil {
+				if len(unresolved) > 0 || set < present {
+					// values that can't be compared can't be classified either
+					drift = domain.DriftUnknown
+				} else {
+					drift = max(drift, classifyDrift(values))
 				}
+			}
+			if isOutOfSync(status) && len(attribute.complex[moduleName]) > 0 {
+				valueDiffs = append(valueDiffs, diffComplexValues(attribute.key, values, attribute.complex[moduleName], sourceLabels)...)
+			}
 
-				if len(diffOutput) > 0 {
-					diffResult = &domain.DiffResult{
-						Output:    diffOutput,
-						BaseLabel: diffCfg.BaseLabel,
-						HeadLabel: diffCfg.HeadLabel,
-						BaseRef:   baseRef,
-						HeadRef:   headRef,
-					}
-				}
+			statuses = append(statuses, status)
+		}
+
+		primary := attributes[0]
+		values := make(map[string]string)
+		for _, label := range sourceLabels {
+			if value, exists := primary.values[moduleName][label]; exists {
+				values[label] = value
 			}
 		}
 
 		moduleResults = append(moduleResults, domain.ModuleResult{
 			Name:       moduleName,
 			Values:     values,
-			Status:     status,
-			DiffResult: diffResult,
+			Status:     combineStatuses(statuses),
+			Drift:      drift,
+			Missing:    isMissing,
+			Unresolved: primary.unresolved[moduleName],
+			ValueDiffs: valueDiffs,
+			Attributes: attributeValues,
 		})
 	}
 
 	return domain.ComparisonResult{
-		SourceLabels: sourceLabels,
-		Modules:      moduleResults,
-	}, nil
+		SourceLabels:         sourceLabels,
+		AttributeKeys:        attributeKeys,
+		Modules:              moduleResults,
+		SemverCfg:            semverCfg,
+		IgnoreMissingModules: ignoreMissingModules,
+	}
+}
+
+// combineStatuses returns the status of a module from the ones of its
+// attributes; being ahead of upstream takes precedence over being out of sync.
+func combineStatuses(statuses []domain.ModuleStatus) domain.ModuleStatus {
+	switch {
+	case slices.Contains(statuses, domain.StatusAheadOfUpstream):
+		return domain.StatusAheadOfUpstream
+	case slices.Contains(statuses, domain.StatusOutOfSync):
+		return domain.StatusOutOfSync
+	case slices.Contains(statuses, domain.StatusInSync):
+		return domain.StatusInSync
+	default:
+		return domain.StatusNotApplicable
+	}
+}
+
+// addDiffs runs the diff command of every diff config for every out-of-sync
+// module that has differing values for the config's base and head labels.
+// Commands are run concurrently, but results are stored against their own
+// modules (in the order of the diff configs), which keeps the output
+// independent of the order in which commands finish. If continueOnErrors is
+// set, a failing command is recorded on its module, and the remaining commands
+// are still run; cancellation of ctx is always returned.
+func addDiffs(ctx context.Context, modules []domain.ModuleResult, diffCfgs []domain.DiffConfig, jobs int, continueOnErrors bool, caching diffCaching) error {
+	type diffJob struct {
+		moduleIndex int
+		diffCfg     domain.DiffConfig
+		result      domain.DiffResult
+		err         *domain.DiffError
+	}
+
+	var diffJobs []diffJob
+	for i, module := range modules {
+		if !isOutOfSync(module.Status) {
+			continue
+		}
+
+		for _, diffCfg := range diffCfgs {
+			baseRef, baseExists := module.Values[diffCfg.BaseLabel]
+			headRef, headExists := module.Values[diffCfg.HeadLabel]
+			if baseExists && headExists && (baseRef != headRef) {
+				diffJobs = append(diffJobs, diffJob{moduleIndex: i, diffCfg: diffCfg})
+			}
+		}
+	}
+
+	err := runConcurrently(len(diffJobs), jobs, func(i int) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		job := &diffJobs[i]
+		module := modules[job.moduleIndex]
+		baseRef := module.Values[job.diffCfg.BaseLabel]
+		headRef := module.Values[job.diffCfg.HeadLabel]
+
+		diffResult, err := computeDiff(ctx, caching, module.Name, job.diffCfg, baseRef, headRef)
+		if err != nil {
+			if continueOnErrors && ctx.Err() == nil {
+				job.err = newDiffError(err, job.diffCfg, baseRef, headRef)
+				return nil
+			}
+
+			return err
+		}
+
+		job.result = diffResult
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, job := range diffJobs {
+		module := &modules[job.moduleIndex]
+		switch {
+		case job.err != nil:
+			module.DiffErrors = append(module.DiffErrors, *job.err)
+		case len(job.result.Output) > 0 || len(job.result.Commits) > 0:
+			module.DiffResults = append(module.DiffResults, job.result)
+		}
+	}
+
+	return nil
+}
+
+// computeDiff runs the diff command for a module, followed by the log command,
+// if one is configured.
+func computeDiff(ctx context.Context, caching diffCaching, moduleName string, diffCfg domain.DiffConfig, baseRef, headRef string) (domain.DiffResult, error) {
+	var zero domain.DiffResult
+
+	command := diffCommand(diffCfg, moduleName, baseRef, headRef)
+	diffOutput, err := runCachedDiffCommand(ctx, caching, moduleName, baseRef, headRef, command, diffCfg.Timeout)
+	if err != nil {
+		return zero, fmt.Errorf("%w for module %q (command: %v): %w", ErrCouldntComputeDiff, moduleName, command, err)
+	}
+
+	result := domain.DiffResult{
+		Output:       diffOutput,
+		BaseLabel:    diffCfg.BaseLabel,
+		HeadLabel:    diffCfg.HeadLabel,
+		BaseRef:      baseRef,
+		HeadRef:      headRef,
+		OutputIsHTML: diffCfg.OutputIsHTML,
+	}
+
+	logCmd := logCommand(diffCfg, moduleName, baseRef, headRef)
+	if len(logCmd) == 0 {
+		return result, nil
+	}
+
+	logOutput, err := runCachedDiffCommand(ctx, caching, moduleName, baseRef, headRef, logCmd, diffCfg.Timeout)
+	if err != nil {
+		return zero, fmt.Errorf("%w for module %q (command: %v): %w", ErrCouldntComputeCommitLog, moduleName, logCmd, err)
+	}
+	result.Commits = parseCommitLog(logOutput)
+
+	return result, nil
+}
+
+// diffCommand returns the command to run for a module's diff; for the built-in
+// git provider, refs and the path are derived from the configured templates.
+func diffCommand(diffCfg domain.DiffConfig, moduleName, baseRef, headRef string) []string {
+	if diffCfg.Git == nil {
+		return diffCfg.Cmd
+	}
+
+	return git.DiffCommand(
+		diffCfg.Git.RepoPath,
+		diffCfg.Git.Ref(moduleName, baseRef),
+		diffCfg.Git.Ref(moduleName, headRef),
+		diffCfg.Git.Path(moduleName, headRef),
+	)
+}
+
+// logCommand returns the command to run for listing the commits between a
+// module's refs, or nil if no commit log is to be collected.
+func logCommand(diffCfg domain.DiffConfig, moduleName, baseRef, headRef string) []string {
+	if diffCfg.Git == nil {
+		return diffCfg.LogCmd
+	}
+
+	if !diffCfg.Git.Log {
+		return nil
+	}
+
+	return git.LogCommand(
+		diffCfg.Git.RepoPath,
+		diffCfg.Git.Ref(moduleName, baseRef),
+		diffCfg.Git.Ref(moduleName, headRef),
+		diffCfg.Git.Path(moduleName, headRef),
+	)
+}
+
+// parseCommitLog expects one commit per line, in the form "<hash> <subject>",
+// as printed by "git log --oneline".
+func parseCommitLog(output []byte) []domain.Commit {
+	var commits []domain.Commit
+	for line := range strings.Lines(string(output)) {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		hash, subject, _ := strings.Cut(line, " ")
+		commits = append(commits, domain.Commit{
+			Hash:    hash,
+			Subject: strings.TrimSpace(subject),
+		})
+	}
+
+	return commits
+}
+
+func newDiffError(err error, diffCfg domain.DiffConfig, baseRef, headRef string) *domain.DiffError {
+	diffErr := domain.DiffError{
+		BaseLabel: diffCfg.BaseLabel,
+		HeadLabel: diffCfg.HeadLabel,
+		BaseRef:   baseRef,
+		HeadRef:   headRef,
+		ExitCode:  -1,
+		Message:   err.Error(),
+	}
+
+	var cmdErr *diffCommandError
+	if errors.As(err, &cmdErr) {
+		diffErr.ExitCode = cmdErr.exitCode
+		diffErr.Message = "command exited with non success exit code"
+		diffErr.Stderr = tailLines(cmdErr.stderr, maxDiffErrorStderrLines)
+	}
+
+	return &diffErr
+}
+
+func tailLines(s string, n int) string {
+	lines := strings.Split(strings.TrimRight(s, "\n"), "\n")
+	if len(lines) <= n {
+		return strings.Join(lines, "\n")
+	}
+
+	return strings.Join(lines[len(lines)-n:], "\n")
+}
+
+func isOutOfSync(status domain.ModuleStatus) bool {
+	return status == domain.StatusOutOfSync || status == domain.StatusAheadOfUpstream
 }
 
 func determineModuleStatus(values map[string]string, isMissing, ignoreMissingModules bool) domain.ModuleStatus {
@@ -173,18 +610,136 @@ func determineModuleStatus(values map[string]string, isMissing, ignoreMissingMod
 	return domain.StatusOutOfSync
 }
 
-func generateDiff(moduleName, baseLabel, headLabel string, command []string) ([]byte, error) {
+// classifyDrift returns the most severe drift between any two of the values
+func classifyDrift(values map[string]string) domain.Drift {
+	var versions []domain.Version
+	for _, value := range values {
+		if value == "" {
+			continue
+		}
+
+		version, ok := domain.ParseVersion(value)
+		if !ok {
+			return domain.DriftUnknown
+		}
+		versions = append(versions, version)
+	}
+
+	drift := domain.DriftNone
+	for i := range versions {
+		for j := i + 1; j < len(versions); j++ {
+			drift = max(drift, domain.ClassifyDrift(versions[i], versions[j]))
+		}
+	}
+
+	return drift
+}
+
+// isAheadOfUpstream reports whether the value for any label in the promotion
+// order is a higher version than the one for the closest upstream label that
+// has a value. Values that are not valid semver versions are not considered.
+func isAheadOfUpstream(values map[string]string, promotionOrder []string) bool {
+	var upstream *domain.Version
+	for _, label := range promotionOrder {
+		value, ok := values[label]
+		if !ok || value == "" {
+			continue
+		}
+
+		version, ok := domain.ParseVersion(value)
+		if !ok {
+			continue
+		}
+
+		if upstream != nil && version.Compare(*upstream) > 0 {
+			return true
+		}
+		upstream = &version
+	}
+
+	return false
+}
+
+// diffCaching holds what's needed to cache the output of diff and log
+// commands; a nil cache disables caching.
+type diffCaching struct {
+	cache *cache.Cache
+	// the absolute working directory; commands (and repo paths) can be
+	// relative to it, so it's part of every key, which keeps the entries of
+	// different projects sharing a cache apart
+	workDir string
+	// called when output couldn't be cached; the output is still used
+	warn func(error)
+}
+
+// newDiffCaching sets up caching with diffCache; caching is disabled (with a
+// warning) if the working directory can't be determined.
+func newDiffCaching(diffCache *cache.Cache, warn func(error)) diffCaching {
+	caching := diffCaching{cache: diffCache, warn: warn}
+	if diffCache == nil {
+		return caching
+	}
+
+	workDir, err := os.Getwd()
+	if err != nil {
+		caching.cache = nil
+		if warn != nil {
+			warn(fmt.Errorf("%w: %w; diffs won't be cached", ErrCouldntDetermineWorkDir, err))
+		}
+		return caching
+	}
+	caching.workDir = workDir
+
+	return caching
+}
+
+// runCachedDiffCommand returns the cached output of a command for a module's
+// refs if present, and runs the command (caching its output) otherwise. Only
+// successful runs are cached.
+func runCachedDiffCommand(ctx context.Context, caching diffCaching, moduleName, baseRef, headRef string, command []string, timeout time.Duration) ([]byte, error) {
+	if caching.cache == nil {
+		return runDiffCommand(ctx, moduleName, baseRef, headRef, command, timeout)
+	}
+
+	key := cache.Key(append([]string{caching.workDir, moduleName, baseRef, headRef}, command...)...)
+	if output, ok := caching.cache.Get(key); ok {
+		return output, nil
+	}
+
+	output, err := runDiffCommand(ctx, moduleName, baseRef, headRef, command, timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	err = caching.cache.Put(key, output)
+	if err != nil && caching.warn != nil {
+		caching.warn(err)
+	}
+
+	return output, nil
+}
+
+func runDiffCommand(ctx context.Context, moduleName, baseLabel, headLabel string, command []string, timeout time.Duration) ([]byte, error) {
 	var zero []byte
 	if len(command) == 0 {
 		return zero, fmt.Errorf("empty command")
 	}
 
-	cmd := exec.Command(command[0], command[1:]...)
+	runCtx := ctx
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		runCtx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	cmd := exec.CommandContext(runCtx, command[0], command[1:]...)
 	cmd.Env = append(os.Environ(),
 		fmt.Sprintf("TFLENS_DIFF_BASE_REF=%s", baseLabel),
 		fmt.Sprintf("TFLENS_DIFF_HEAD_REF=%s", headLabel),
 		fmt.Sprintf("TFLENS_DIFF_MODULE_NAME=%s", moduleName),
 	)
+	cmd.WaitDelay = diffWaitDelay
+	configureProcessGroup(cmd)
 
 	var stdoutBuf, stderrBuf bytes.Buffer
 	cmd.Stdout = &stdoutBuf
@@ -192,16 +747,21 @@ func generateDiff(moduleName, baseLabel, headLabel string, command []string) ([]
 
 	err := cmd.Run()
 	if err != nil {
+		if ctx.Err() != nil {
+			return zero, ctx.Err()
+		}
+
+		if errors.Is(runCtx.Err(), context.DeadlineExceeded) {
+			return zero, fmt.Errorf("%w after %s", ErrDiffTimedOut, timeout)
+		}
+
 		var exitError *exec.ExitError
 		if errors.As(err, &exitError) {
-			exitCode := exitError.ExitCode()
-			return zero, fmt.Errorf(`command exited with non success exit code
-
-exit_code: %d
------ stdout -----
-%s
------ stderr -----
-%s`, exitCode, stdoutBuf.String(), stderrBuf.String())
+			return zero, &diffCommandError{
+				exitCode: exitError.ExitCode(),
+				stdout:   stdoutBuf.String(),
+				stderr:   stderrBuf.String(),
+			}
 		}
 
 		return zero, fmt.Errorf("couldn't run command: %w", err)
@@ -209,3 +769,19 @@ exit_code: %d
 
 	return stdoutBuf.Bytes(), nil
 }
+
+type diffCommandError struct {
+	exitCode int
+	stdout   string
+	stderr   string
+}
+
+func (e *diffCommandError) Error() string {
+	return fmt.Sprintf(`command exited with non success exit code
+
+exit_code: %d
+----- stdout -----
+%s
+----- stderr -----
+%s`, e.exitCode, e.stdout, e.stderr)
+}
@@ -1,11 +1,23 @@
 package services
 
 import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
 	"regexp"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/dhth/tflens/internal/cache"
 	"github.com/dhth/tflens/internal/domain"
+	"github.com/dhth/tflens/internal/git"
+	"github.com/dhth/tflens/internal/hcl"
 	"github.com/gkampitakis/go-snaps/snaps"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -15,8 +27,8 @@ func TestGetComparisonResult(t *testing.T) {
 		valueRegex := regexp.MustCompile(`v?(\d+\.\d+\.\d+)`)
 
 		comparison := domain.Comparison{
-			Name:         "test-comparison",
-			AttributeKey: "source",
+			Name:          "test-comparison",
+			AttributeKeys: []string{"source"},
 			Sources: []domain.Source{
 				{
 					Path:  "testdata/environments/qa/main.tf",
@@ -34,7 +46,7 @@ func TestGetComparisonResult(t *testing.T) {
 		}
 
 		// WHEN
-		result, err := GetComparisonResult(comparison, valueRegex, false, false)
+		result, err := GetComparisonResult(t.Context(), comparison, ComparisonOptions{GlobalValueRegex: valueRegex})
 
 		// THEN
 		require.NoError(t, err)
@@ -46,8 +58,8 @@ func TestGetComparisonResult(t *testing.T) {
 		valueRegex := regexp.MustCompile(`v?(\d+\.\d+\.\d+)`)
 
 		comparison := domain.Comparison{
-			Name:         "test-comparison",
-			AttributeKey: "source",
+			Name:          "test-comparison",
+			AttributeKeys: []string{"source"},
 			Sources: []domain.Source{
 				{
 					Path:  "testdata/environments/qa/main.tf",
@@ -65,7 +77,7 @@ func TestGetComparisonResult(t *testing.T) {
 		}
 
 		// WHEN
-		result, err := GetComparisonResult(comparison, valueRegex, true, false)
+		result, err := GetComparisonResult(t.Context(), comparison, ComparisonOptions{GlobalValueRegex: valueRegex, IgnoreMissingModules: true})
 
 		// THEN
 		require.NoError(t, err)
@@ -77,8 +89,8 @@ func TestGetComparisonResult(t *testing.T) {
 		valueRegex := regexp.MustCompile(`v?(\d+\.\d+\.\d+)`)
 
 		comparison := domain.Comparison{
-			Name:         "test-comparison-sync",
-			AttributeKey: "source",
+			Name:          "test-comparison-sync",
+			AttributeKeys: []string{"source"},
 			Sources: []domain.Source{
 				{
 					Path:  "testdata/environments/staging/main.tf",
@@ -93,59 +105,1379 @@ func TestGetComparisonResult(t *testing.T) {
 		}
 
 		// WHEN
-		result, err := GetComparisonResult(comparison, valueRegex, false, false)
+		result, err := GetComparisonResult(t.Context(), comparison, ComparisonOptions{GlobalValueRegex: valueRegex})
 
 		// THEN
 		require.NoError(t, err)
 		snaps.MatchYAML(t, result)
 	})
+
+	t.Run("source valueRegex takes precedence over comparison and global ones", func(t *testing.T) {
+		// GIVEN
+		globalValueRegex := regexp.MustCompile(`ref=(.+)`)
+
+		comparison := domain.Comparison{
+			Name:          "test-comparison-value-regex",
+			AttributeKeys: []string{"source"},
+			Sources: []domain.Source{
+				{
+					Path:  "testdata/environments/qa/main.tf",
+					Label: "qa",
+				},
+				{
+					Path:       "testdata/environments/staging/main.tf",
+					Label:      "staging",
+					ValueRegex: regexp.MustCompile(`v?(\d+\.\d+)\.\d+`),
+				},
+				{
+					Path:  "testdata/environments/prod/main.tf",
+					Label: "prod",
+				},
+			},
+			ValueRegex: regexp.MustCompile(`v?(\d+\.\d+\.\d+)`),
+		}
+
+		// WHEN
+		result, err := GetComparisonResult(t.Context(), comparison, ComparisonOptions{GlobalValueRegex: globalValueRegex})
+
+		// THEN
+		require.NoError(t, err)
+		snaps.MatchYAML(t, result)
+	})
+
+	t.Run("works for directory and glob sources", func(t *testing.T) {
+		// GIVEN
+		valueRegex := regexp.MustCompile(`v?(\d+\.\d+\.\d+)`)
+
+		comparison := domain.Comparison{
+			Name:          "test-comparison-dirs",
+			AttributeKeys: []string{"source"},
+			Sources: []domain.Source{
+				{
+					Path:  "testdata/environments/dev",
+					Label: "dev",
+				},
+				{
+					Path:  "testdata/environments/uat/**/*.tf",
+					Label: "uat",
+				},
+			},
+		}
+
+		// WHEN
+		result, err := GetComparisonResult(t.Context(), comparison, ComparisonOptions{GlobalValueRegex: valueRegex})
+
+		// THEN
+		require.NoError(t, err)
+		snaps.MatchYAML(t, result)
+	})
+
+	t.Run("works for terragrunt sources", func(t *testing.T) {
+		// GIVEN
+		valueRegex := regexp.MustCompile(`v?(\d+\.\d+\.\d+)`)
+
+		comparison := domain.Comparison{
+			Name:          "test-comparison-terragrunt",
+			AttributeKeys: []string{"source"},
+			Sources: []domain.Source{
+				{
+					Path:  "testdata/terragrunt/qa",
+					Label: "qa",
+					Kind:  domain.TerragruntSource,
+				},
+				{
+					Path:  "testdata/terragrunt/prod",
+					Label: "prod",
+					Kind:  domain.TerragruntSource,
+				},
+			},
+		}
+
+		// WHEN
+		result, err := GetComparisonResult(t.Context(), comparison, ComparisonOptions{GlobalValueRegex: valueRegex})
+
+		// THEN
+		require.NoError(t, err)
+		snaps.MatchYAML(t, result)
+	})
+
+	t.Run("generating diffs concurrently works", func(t *testing.T) {
+		// GIVEN
+		valueRegex := regexp.MustCompile(`v?(\d+\.\d+\.\d+)`)
+
+		comparison := domain.Comparison{
+			Name:          "test-comparison-diffs",
+			AttributeKeys: []string{"source"},
+			Sources: []domain.Source{
+				{
+					Path:  "testdata/environments/qa/main.tf",
+					Label: "qa",
+				},
+				{
+					Path:  "testdata/environments/staging/main.tf",
+					Label: "staging",
+				},
+				{
+					Path:  "testdata/environments/prod/main.tf",
+					Label: "prod",
+				},
+			},
+			DiffCfgs: []domain.DiffConfig{{
+				BaseLabel: "prod",
+				HeadLabel: "qa",
+				Cmd:       []string{"sh", "-c", `echo "$TFLENS_DIFF_MODULE_NAME: $TFLENS_DIFF_BASE_REF..$TFLENS_DIFF_HEAD_REF"`},
+			}},
+		}
+
+		// WHEN
+		result, err := GetComparisonResult(t.Context(), comparison, ComparisonOptions{
+			GlobalValueRegex: valueRegex,
+			IncludeDiffs:     true,
+			Jobs:             4,
+		})
+
+		// THEN
+		require.NoError(t, err)
+		diffs := make(map[string]string)
+		for _, module := range result.Modules {
+			for _, diffResult := range module.DiffResults {
+				diffs[module.Name] = string(diffResult.Output)
+			}
+		}
+		snaps.MatchYAML(t, diffs)
+	})
+
+	t.Run("generating diffs for multiple pairs of labels works", func(t *testing.T) {
+		// GIVEN
+		valueRegex := regexp.MustCompile(`v?(\d+\.\d+\.\d+)`)
+
+		comparison := domain.Comparison{
+			Name:          "test-comparison-diff-pairs",
+			AttributeKeys: []string{"source"},
+			Sources: []domain.Source{
+				{
+					Path:  "testdata/environments/qa/main.tf",
+					Label: "qa",
+				},
+				{
+					Path:  "testdata/environments/staging/main.tf",
+					Label: "staging",
+				},
+				{
+					Path:  "testdata/environments/prod/main.tf",
+					Label: "prod",
+				},
+			},
+			DiffCfgs: []domain.DiffConfig{
+				{
+					BaseLabel: "staging",
+					HeadLabel: "qa",
+					Cmd:       []string{"sh", "-c", `echo "qa: $TFLENS_DIFF_BASE_REF..$TFLENS_DIFF_HEAD_REF"`},
+				},
+				{
+					BaseLabel: "prod",
+					HeadLabel: "staging",
+					Cmd:       []string{"sh", "-c", `echo "staging: $TFLENS_DIFF_BASE_REF..$TFLENS_DIFF_HEAD_REF"`},
+				},
+			},
+		}
+
+		// WHEN
+		result, err := GetComparisonResult(t.Context(), comparison, ComparisonOptions{
+			GlobalValueRegex: valueRegex,
+			IncludeDiffs:     true,
+			Jobs:             4,
+		})
+
+		// THEN
+		require.NoError(t, err)
+		diffs := make(map[string][]string)
+		for _, module := range result.Modules {
+			for _, diffResult := range module.DiffResults {
+				diffs[module.Name] = append(diffs[module.Name], string(diffResult.Output))
+			}
+		}
+		snaps.MatchYAML(t, diffs)
+	})
+
+	t.Run("collecting commit logs via a log command works", func(t *testing.T) {
+		// GIVEN
+		valueRegex := regexp.MustCompile(`v?(\d+\.\d+\.\d+)`)
+
+		comparison := domain.Comparison{
+			Name:          "test-comparison-diffs",
+			AttributeKeys: []string{"source"},
+			Sources: []domain.Source{
+				{
+					Path:  "testdata/environments/qa/main.tf",
+					Label: "qa",
+				},
+				{
+					Path:  "testdata/environments/prod/main.tf",
+					Label: "prod",
+				},
+			},
+			DiffCfgs: []domain.DiffConfig{{
+				BaseLabel: "prod",
+				HeadLabel: "qa",
+				Cmd:       []string{"sh", "-c", "true"},
+				LogCmd: []string{"sh", "-c", `
+printf "a1b2c3d $TFLENS_DIFF_MODULE_NAME: release $TFLENS_DIFF_HEAD_REF\n\n"
+printf "e4f5a6b $TFLENS_DIFF_MODULE_NAME: fix a bug\n"
+`},
+			}},
+		}
+
+		// WHEN
+		result, err := GetComparisonResult(t.Context(), comparison, ComparisonOptions{
+			GlobalValueRegex: valueRegex,
+			IncludeDiffs:     true,
+		})
+
+		// THEN
+		require.NoError(t, err)
+		commits := make(map[string][]domain.Commit)
+		for _, module := range result.Modules {
+			for _, diffResult := range module.DiffResults {
+				assert.Empty(t, diffResult.Output)
+				commits[module.Name] = diffResult.Commits
+			}
+		}
+		snaps.MatchYAML(t, commits)
+	})
+
+	t.Run("diffs are reused from the cache", func(t *testing.T) {
+		// GIVEN
+		valueRegex := regexp.MustCompile(`v?(\d+\.\d+\.\d+)`)
+		tempDir := t.TempDir()
+		runsPath := filepath.Join(tempDir, "runs")
+		diffCache, err := cache.New(filepath.Join(tempDir, "cache"), time.Hour, 0)
+		require.NoError(t, err)
+
+		comparison := domain.Comparison{
+			Name:          "test-comparison-cached-diffs",
+			AttributeKeys: []string{"source"},
+			Sources: []domain.Source{
+				{
+					Path:  "testdata/environments/qa/main.tf",
+					Label: "qa",
+				},
+				{
+					Path:  "testdata/environments/prod/main.tf",
+					Label: "prod",
+				},
+			},
+			DiffCfgs: []domain.DiffConfig{{
+				BaseLabel: "prod",
+				HeadLabel: "qa",
+				Cmd: []string{"sh", "-c", fmt.Sprintf(
+					`echo "$TFLENS_DIFF_MODULE_NAME" >> %q && echo "$TFLENS_DIFF_BASE_REF..$TFLENS_DIFF_HEAD_REF"`,
+					runsPath,
+				)},
+			}},
+		}
+		opts := ComparisonOptions{
+			GlobalValueRegex: valueRegex,
+			IncludeDiffs:     true,
+			DiffCache:        diffCache,
+		}
+		firstResult, err := GetComparisonResult(t.Context(), comparison, opts)
+		require.NoError(t, err)
+		runsBefore, err := os.ReadFile(runsPath)
+		require.NoError(t, err)
+		require.NotEmpty(t, runsBefore)
+
+		// WHEN
+		result, err := GetComparisonResult(t.Context(), comparison, opts)
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, firstResult, result)
+		runsAfter, err := os.ReadFile(runsPath)
+		require.NoError(t, err)
+		assert.Equal(t, string(runsBefore), string(runsAfter))
+	})
+
+	t.Run("cached diffs aren't shared across working directories", func(t *testing.T) {
+		// GIVEN
+		valueRegex := regexp.MustCompile(`v?(\d+\.\d+\.\d+)`)
+		qaPath, err := filepath.Abs("testdata/environments/qa/main.tf")
+		require.NoError(t, err)
+		prodPath, err := filepath.Abs("testdata/environments/prod/main.tf")
+		require.NoError(t, err)
+		diffCache, err := cache.New(filepath.Join(t.TempDir(), "cache"), time.Hour, 0)
+		require.NoError(t, err)
+
+		firstDir := t.TempDir()
+		secondDir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(firstDir, "generate-diff.sh"), []byte("echo first\n"), 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(secondDir, "generate-diff.sh"), []byte("echo second\n"), 0o755))
+
+		comparison := domain.Comparison{
+			Name:          "test-comparison-cached-diffs-per-dir",
+			AttributeKeys: []string{"source"},
+			Sources: []domain.Source{
+				{
+					Path:  qaPath,
+					Label: "qa",
+				},
+				{
+					Path:  prodPath,
+					Label: "prod",
+				},
+			},
+			DiffCfgs: []domain.DiffConfig{{
+				BaseLabel: "prod",
+				HeadLabel: "qa",
+				Cmd:       []string{"sh", "./generate-diff.sh"},
+			}},
+		}
+		opts := ComparisonOptions{
+			GlobalValueRegex: valueRegex,
+			IncludeDiffs:     true,
+			DiffCache:        diffCache,
+		}
+		t.Chdir(firstDir)
+		firstResult, err := GetComparisonResult(t.Context(), comparison, opts)
+		require.NoError(t, err)
+
+		// WHEN
+		t.Chdir(secondDir)
+		result, err := GetComparisonResult(t.Context(), comparison, opts)
+
+		// THEN
+		require.NoError(t, err)
+		outputs := func(result domain.ComparisonResult) []string {
+			var outputs []string
+			for _, module := range result.Modules {
+				for _, diffResult := range module.DiffResults {
+					outputs = append(outputs, string(diffResult.Output))
+				}
+			}
+			return outputs
+		}
+		require.NotEmpty(t, outputs(firstResult))
+		for _, output := range outputs(firstResult) {
+			assert.Equal(t, "first\n", output)
+		}
+		for _, output := range outputs(result) {
+			assert.Equal(t, "second\n", output)
+		}
+	})
+
+	t.Run("diffs are returned even if they can't be cached", func(t *testing.T) {
+		// GIVEN
+		valueRegex := regexp.MustCompile(`v?(\d+\.\d+\.\d+)`)
+		cacheDir := filepath.Join(t.TempDir(), "cache")
+		diffCache, err := cache.New(cacheDir, time.Hour, 0)
+		require.NoError(t, err)
+		require.NoError(t, os.RemoveAll(cacheDir))
+
+		comparison := domain.Comparison{
+			Name:          "test-comparison-uncacheable-diffs",
+			AttributeKeys: []string{"source"},
+			Sources: []domain.Source{
+				{
+					Path:  "testdata/environments/qa/main.tf",
+					Label: "qa",
+				},
+				{
+					Path:  "testdata/environments/prod/main.tf",
+					Label: "prod",
+				},
+			},
+			DiffCfgs: []domain.DiffConfig{{
+				BaseLabel: "prod",
+				HeadLabel: "qa",
+				Cmd:       []string{"sh", "-c", `echo "$TFLENS_DIFF_BASE_REF..$TFLENS_DIFF_HEAD_REF"`},
+			}},
+		}
+		var warnings []error
+		var mu sync.Mutex
+		opts := ComparisonOptions{
+			GlobalValueRegex: valueRegex,
+			IncludeDiffs:     true,
+			DiffCache:        diffCache,
+			Warn: func(err error) {
+				mu.Lock()
+				defer mu.Unlock()
+				warnings = append(warnings, err)
+			},
+		}
+
+		// WHEN
+		result, err := GetComparisonResult(t.Context(), comparison, opts)
+
+		// THEN
+		require.NoError(t, err)
+		assert.False(t, result.HasDiffErrors())
+		require.NotEmpty(t, warnings)
+		assert.ErrorIs(t, warnings[0], cache.ErrCouldntWriteCacheEntry)
+		var outputs int
+		for _, module := range result.Modules {
+			for _, diffResult := range module.DiffResults {
+				assert.NotEmpty(t, diffResult.Output)
+				outputs++
+			}
+		}
+		assert.Positive(t, outputs)
+	})
+
+	t.Run("fails when a module is declared twice in a source", func(t *testing.T) {
+		// GIVEN
+		comparison := domain.Comparison{
+			Name:          "test-comparison-duplicates",
+			AttributeKeys: []string{"source"},
+			Sources: []domain.Source{
+				{
+					Path:  "testdata/environments/qa/main.tf",
+					Label: "qa",
+				},
+				{
+					Path:  "testdata/duplicates",
+					Label: "duplicates",
+				},
+			},
+		}
+
+		// WHEN
+		_, err := GetComparisonResult(t.Context(), comparison, ComparisonOptions{})
+
+		// THEN
+		require.ErrorIs(t, err, hcl.ErrDuplicateModule)
+		assert.Contains(t, err.Error(), `"module_a" is declared at testdata/duplicates/apps.tf:1,1-18 and testdata/duplicates/main.tf:1,1-18`)
+	})
+
+	t.Run("fails with the error for the first module when diff commands fail", func(t *testing.T) {
+		// GIVEN
+		valueRegex := regexp.MustCompile(`v?(\d+\.\d+\.\d+)`)
+
+		comparison := domain.Comparison{
+			Name:          "test-comparison-diffs",
+			AttributeKeys: []string{"source"},
+			Sources: []domain.Source{
+				{
+					Path:  "testdata/environments/qa/main.tf",
+					Label: "qa",
+				},
+				{
+					Path:  "testdata/environments/prod/main.tf",
+					Label: "prod",
+				},
+			},
+			DiffCfgs: []domain.DiffConfig{{
+				BaseLabel: "prod",
+				HeadLabel: "qa",
+				Cmd:       []string{"sh", "-c", "exit 1"},
+			}},
+		}
+
+		// WHEN
+		_, err := GetComparisonResult(t.Context(), comparison, ComparisonOptions{
+			GlobalValueRegex: valueRegex,
+			IncludeDiffs:     true,
+			Jobs:             4,
+		})
+
+		// THEN
+		require.ErrorIs(t, err, ErrCouldntComputeDiff)
+		assert.Contains(t, err.Error(), `module "module_a"`)
+	})
+
+	t.Run("records diff failures on modules when continuing on diff errors", func(t *testing.T) {
+		// GIVEN
+		valueRegex := regexp.MustCompile(`v?(\d+\.\d+\.\d+)`)
+
+		comparison := domain.Comparison{
+			Name:          "test-comparison-diffs",
+			AttributeKeys: []string{"source"},
+			Sources: []domain.Source{
+				{
+					Path:  "testdata/environments/qa/main.tf",
+					Label: "qa",
+				},
+				{
+					Path:  "testdata/environments/prod/main.tf",
+					Label: "prod",
+				},
+			},
+			DiffCfgs: []domain.DiffConfig{{
+				BaseLabel: "prod",
+				HeadLabel: "qa",
+				Cmd: []string{"sh", "-c", `
+if [ "$TFLENS_DIFF_MODULE_NAME" = "module_a" ]; then
+	echo "fatal: bad revision" >&2
+	exit 128
+fi
+echo "$TFLENS_DIFF_MODULE_NAME: $TFLENS_DIFF_BASE_REF..$TFLENS_DIFF_HEAD_REF"
+`},
+			}},
+		}
+
+		// WHEN
+		result, err := GetComparisonResult(t.Context(), comparison, ComparisonOptions{
+			GlobalValueRegex:     valueRegex,
+			IncludeDiffs:         true,
+			Jobs:                 4,
+			ContinueOnDiffErrors: true,
+		})
+
+		// THEN
+		require.NoError(t, err)
+		assert.True(t, result.HasDiffErrors())
+		diffs := make(map[string]string)
+		diffErrors := make(map[string]domain.DiffError)
+		for _, module := range result.Modules {
+			for _, diffResult := range module.DiffResults {
+				diffs[module.Name] = string(diffResult.Output)
+			}
+			for _, diffErr := range module.DiffErrors {
+				diffErrors[module.Name] = diffErr
+			}
+		}
+		snaps.MatchYAML(t, map[string]any{
+			"diffs":      diffs,
+			"diffErrors": diffErrors,
+		})
+	})
+
+	t.Run("fails with a timeout error naming the module when a diff command hangs", func(t *testing.T) {
+		// GIVEN
+		valueRegex := regexp.MustCompile(`v?(\d+\.\d+\.\d+)`)
+
+		comparison := domain.Comparison{
+			Name:          "test-comparison-diffs",
+			AttributeKeys: []string{"source"},
+			Sources: []domain.Source{
+				{
+					Path:  "testdata/environments/qa/main.tf",
+					Label: "qa",
+				},
+				{
+					Path:  "testdata/environments/prod/main.tf",
+					Label: "prod",
+				},
+			},
+			DiffCfgs: []domain.DiffConfig{{
+				BaseLabel: "prod",
+				HeadLabel: "qa",
+				// the background process keeps the output pipes open; it needs
+				// to be killed along with the shell for the command to finish
+				Cmd:     []string{"sh", "-c", "sleep 30 & wait"},
+				Timeout: 30 * time.Second,
+			}},
+		}
+		start := time.Now()
+
+		// WHEN
+		_, err := GetComparisonResult(t.Context(), comparison, ComparisonOptions{
+			GlobalValueRegex: valueRegex,
+			IncludeDiffs:     true,
+			Jobs:             4,
+			DiffTimeout:      200 * time.Millisecond,
+		})
+
+		// THEN
+		require.ErrorIs(t, err, ErrDiffTimedOut)
+		assert.Contains(t, err.Error(), `module "module_a"`)
+		assert.Contains(t, err.Error(), "timed out after 200ms")
+		assert.Less(t, time.Since(start), 3*time.Second)
+	})
+
+	t.Run("stops generating diffs when the context is cancelled", func(t *testing.T) {
+		// GIVEN
+		valueRegex := regexp.MustCompile(`v?(\d+\.\d+\.\d+)`)
+
+		comparison := domain.Comparison{
+			Name:          "test-comparison-diffs",
+			AttributeKeys: []string{"source"},
+			Sources: []domain.Source{
+				{
+					Path:  "testdata/environments/qa/main.tf",
+					Label: "qa",
+				},
+				{
+					Path:  "testdata/environments/prod/main.tf",
+					Label: "prod",
+				},
+			},
+			DiffCfgs: []domain.DiffConfig{{
+				BaseLabel: "prod",
+				HeadLabel: "qa",
+				Cmd:       []string{"sh", "-c", "sleep 30 & wait"},
+			}},
+		}
+		ctx, cancel := context.WithTimeout(t.Context(), 200*time.Millisecond)
+		defer cancel()
+		start := time.Now()
+
+		// WHEN
+		_, err := GetComparisonResult(ctx, comparison, ComparisonOptions{
+			GlobalValueRegex: valueRegex,
+			IncludeDiffs:     true,
+			Jobs:             1,
+		})
+
+		// THEN
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.NotErrorIs(t, err, ErrDiffTimedOut)
+		assert.Less(t, time.Since(start), 3*time.Second)
+	})
+}
+
+func TestBuildComparisonResult(t *testing.T) {
+	store := map[string]map[string]string{
+		"module_a": {
+			"qa":      "1.2.3",
+			"staging": "1.2.3",
+			"prod":    "1.2.3",
+		},
+		"module_b": {
+			"qa":      "1.0.0",
+			"staging": "1.1.0",
+			"prod":    "1.2.0",
+		},
+		"module_c": {
+			"qa":      "2.0.0",
+			"staging": "2.0.0",
+			"prod":    "",
+		},
+		"module_d": {
+			"qa":      "3.0.0",
+			"staging": "",
+			"prod":    "",
+		},
+	}
+
+	t.Run("works when missing modules are not ignored", func(t *testing.T) {
+		// GIVEN
+		sourceLabels := []string{"qa", "staging", "prod"}
+
+		// WHEN
+		result := buildComparisonResult([]attributeStore{{values: store}}, sourceLabels, false, nil, nil)
+
+		// THEN
+		snaps.MatchYAML(t, result)
+	})
+
+	t.Run("works when missing modules are ignored", func(t *testing.T) {
+		// GIVEN
+		sourceLabels := []string{"qa", "staging", "prod"}
+
+		// WHEN
+		result := buildComparisonResult([]attributeStore{{values: store}}, sourceLabels, true, nil, nil)
+
+		// THEN
+		snaps.MatchYAML(t, result)
+	})
+
+	t.Run("classifies drift when semver is enabled", func(t *testing.T) {
+		// GIVEN
+		sourceLabels := []string{"qa", "staging", "prod"}
+		semverStore := map[string]map[string]string{
+			"module_a": {
+				"qa":      "1.2.3",
+				"staging": "1.2.3",
+				"prod":    "1.2.3",
+			},
+			"module_b": {
+				"qa":      "1.0.0",
+				"staging": "1.1.0",
+				"prod":    "1.1.2",
+			},
+			"module_c": {
+				"qa":      "3.0.0-rc.1",
+				"staging": "2.0.0",
+				"prod":    "2.0.0",
+			},
+			"module_d": {
+				"qa":      "1.0.1",
+				"staging": "1.0.0",
+				"prod":    "1.0.0",
+			},
+			"module_e": {
+				"qa":      "main",
+				"staging": "1.0.0",
+				"prod":    "1.0.0",
+			},
+		}
+		semverCfg := domain.SemverConfig{FailOn: domain.DriftMinor}
+
+		// WHEN
+		result := buildComparisonResult([]attributeStore{{values: semverStore}}, sourceLabels, false, &semverCfg, nil)
+
+		// THEN
+		snaps.MatchYAML(t, result)
+	})
+
+	t.Run("respects ignoring missing modules when classifying failures", func(t *testing.T) {
+		// GIVEN
+		sourceLabels := []string{"qa", "staging", "prod"}
+		store := map[string]map[string]string{
+			"module_a": {
+				"qa":      "1.0.1",
+				"staging": "1.0.0",
+			},
+			"module_b": {
+				"qa":      "1.1.0",
+				"staging": "1.0.0",
+			},
+		}
+		semverCfg := domain.SemverConfig{FailOn: domain.DriftMinor}
+
+		for _, tt := range []struct {
+			ignoreMissingModules bool
+			expected             map[string]bool
+		}{
+			{
+				ignoreMissingModules: true,
+				expected:             map[string]bool{"module_a": false, "module_b": true},
+			},
+			{
+				ignoreMissingModules: false,
+				expected:             map[string]bool{"module_a": true, "module_b": true},
+			},
+		} {
+			// WHEN
+			result := buildComparisonResult([]attributeStore{{values: store}}, sourceLabels, tt.ignoreMissingModules, &semverCfg, nil)
+
+			// THEN
+			failing := make(map[string]bool)
+			for _, module := range result.Modules {
+				assert.True(t, module.Missing)
+				failing[module.Name] = result.IsModuleFailing(module)
+			}
+			assert.Equal(t, tt.expected, failing, "ignoreMissingModules: %t", tt.ignoreMissingModules)
+		}
+	})
+
+	t.Run("flags modules ahead of upstream as per promotion order", func(t *testing.T) {
+		// GIVEN
+		sourceLabels := []string{"dev", "staging", "prod"}
+		promotionStore := map[string]map[string]string{
+			"module_a": {
+				"dev":     "1.2.0",
+				"staging": "1.1.0",
+				"prod":    "1.0.0",
+			},
+			"module_b": {
+				"dev":     "1.0.0",
+				"staging": "1.1.0",
+				"prod":    "1.0.0",
+			},
+			"module_c": {
+				"dev":     "2.0.0",
+				"staging": "",
+				"prod":    "2.1.0",
+			},
+			"module_d": {
+				"dev":     "main",
+				"staging": "1.0.0",
+				"prod":    "1.0.0",
+			},
+			"module_e": {
+				"dev":     "1.0.0",
+				"staging": "1.0.0",
+				"prod":    "1.0.0",
+			},
+		}
+		promotionOrder := []string{"dev", "staging", "prod"}
+
+		// WHEN
+		result := buildComparisonResult([]attributeStore{{values: promotionStore}}, sourceLabels, false, nil, promotionOrder)
+
+		// THEN
+		snaps.MatchYAML(t, result)
+	})
+}
+
+func TestGetComparisonResultWithInterpolatedValues(t *testing.T) {
+	valueRegex := regexp.MustCompile(`v?(\d+\.\d+\.\d+)`)
+
+	//-------------//
+	//  SUCCESSES  //
+	//-------------//
+
+	t.Run("resolves locals, variables, and var files", func(t *testing.T) {
+		// GIVEN
+		comparison := domain.Comparison{
+			Name:          "test-comparison-interpolated",
+			AttributeKeys: []string{"source"},
+			Sources: []domain.Source{
+				{
+					Path:     "testdata/interpolated/dev",
+					Label:    "dev",
+					VarFiles: []string{"testdata/interpolated/dev/dev.tfvars"},
+				},
+				{
+					Path:  "testdata/interpolated/prod",
+					Label: "prod",
+				},
+			},
+		}
+
+		// WHEN
+		result, err := GetComparisonResult(t.Context(), comparison, ComparisonOptions{GlobalValueRegex: valueRegex})
+
+		// THEN
+		require.NoError(t, err)
+		snaps.MatchYAML(t, result)
+	})
+
+	t.Run("uses variable defaults in the absence of var files", func(t *testing.T) {
+		// GIVEN
+		comparison := domain.Comparison{
+			Name:          "test-comparison-interpolated",
+			AttributeKeys: []string{"source"},
+			Sources: []domain.Source{
+				{
+					Path:  "testdata/interpolated/dev",
+					Label: "dev",
+				},
+				{
+					Path:  "testdata/interpolated/prod",
+					Label: "prod",
+				},
+			},
+		}
+
+		// WHEN
+		result, err := GetComparisonResult(t.Context(), comparison, ComparisonOptions{GlobalValueRegex: valueRegex})
+
+		// THEN
+		require.NoError(t, err)
+		snaps.MatchYAML(t, result)
+	})
+
+	t.Run("reports why a variable's default couldn't be evaluated", func(t *testing.T) {
+		// GIVEN
+		sourceDir := t.TempDir()
+		mainTF := `module "module_a" {
+  source = "git@github.com:dhth/infrastructure//modules/applications/module-a?ref=module-a-v${var.module_a_version}"
+}
+`
+		variablesTF := `variable "module_a_version" {
+  type    = string
+  default = local.module_a_version
+}
+`
+		require.NoError(t, os.WriteFile(filepath.Join(sourceDir, "main.tf"), []byte(mainTF), 0o644))
+		require.NoError(t, os.WriteFile(filepath.Join(sourceDir, "variables.tf"), []byte(variablesTF), 0o644))
+		comparison := domain.Comparison{
+			Name:          "test-comparison-invalid-default",
+			AttributeKeys: []string{"source"},
+			Sources: []domain.Source{
+				{
+					Path:  sourceDir,
+					Label: "dev",
+				},
+				{
+					Path:  "testdata/interpolated/prod",
+					Label: "prod",
+				},
+			},
+		}
+
+		// WHEN
+		result, err := GetComparisonResult(t.Context(), comparison, ComparisonOptions{GlobalValueRegex: valueRegex})
+
+		// THEN
+		require.NoError(t, err)
+		require.NotEmpty(t, result.Modules)
+		assert.Equal(t, "module_a", result.Modules[0].Name)
+		reason := result.Modules[0].Unresolved["dev"]
+		assert.Contains(t, reason, `default of variable "module_a_version" couldn't be evaluated`)
+		assert.NotContains(t, reason, "has no default")
+	})
+
+	//------------//
+	//  FAILURES  //
+	//------------//
+
+	t.Run("fails for a var file that can't be parsed", func(t *testing.T) {
+		// GIVEN
+		varFile := filepath.Join(t.TempDir(), "bad.tfvars")
+		require.NoError(t, os.WriteFile(varFile, []byte("module_b_version = \n"), 0o644))
+		comparison := domain.Comparison{
+			Name:          "test-comparison-interpolated",
+			AttributeKeys: []string{"source"},
+			Sources: []domain.Source{
+				{
+					Path:     "testdata/interpolated/dev",
+					Label:    "dev",
+					VarFiles: []string{varFile},
+				},
+			},
+		}
+
+		// WHEN
+		_, err := GetComparisonResult(t.Context(), comparison, ComparisonOptions{GlobalValueRegex: valueRegex})
+
+		// THEN
+		require.ErrorIs(t, err, hcl.ErrCouldntParseVarFile)
+	})
 }
 
-func TestBuildComparisonResult(t *testing.T) {
-	store := map[string]map[string]string{
-		"module_a": {
-			"qa":      "1.2.3",
-			"staging": "1.2.3",
-			"prod":    "1.2.3",
-		},
-		"module_b": {
-			"qa":      "1.0.0",
-			"staging": "1.1.0",
-			"prod":    "1.2.0",
-		},
-		"module_c": {
-			"qa":      "2.0.0",
-			"staging": "2.0.0",
-			"prod":    "",
-		},
-		"module_d": {
-			"qa":      "3.0.0",
-			"staging": "",
-			"prod":    "",
-		},
-	}
+func TestGetComparisonResultWithMultipleAttributes(t *testing.T) {
+	t.Run("compares all attributes", func(t *testing.T) {
+		// GIVEN
+		comparison := domain.Comparison{
+			Name:          "test-comparison-registry",
+			AttributeKeys: []string{"version", "source"},
+			Sources: []domain.Source{
+				{
+					Path:  "testdata/registry/dev",
+					Label: "dev",
+				},
+				{
+					Path:  "testdata/registry/prod",
+					Label: "prod",
+				},
+			},
+		}
 
-	t.Run("works when missing modules are not ignored", func(t *testing.T) {
+		// WHEN
+		result, err := GetComparisonResult(t.Context(), comparison, ComparisonOptions{})
+
+		// THEN
+		require.NoError(t, err)
+		snaps.MatchYAML(t, result)
+	})
+
+	t.Run("classifies drift across attributes", func(t *testing.T) {
 		// GIVEN
-		sourceLabels := []string{"qa", "staging", "prod"}
+		comparison := domain.Comparison{
+			Name:          "test-comparison-registry",
+			AttributeKeys: []string{"version", "source"},
+			Sources: []domain.Source{
+				{
+					Path:  "testdata/registry/dev",
+					Label: "dev",
+				},
+				{
+					Path:  "testdata/registry/prod",
+					Label: "prod",
+				},
+			},
+			SemverCfg: &domain.SemverConfig{FailOn: domain.DriftMajor},
+		}
 
 		// WHEN
-		result, err := buildComparisonResult(store, sourceLabels, false, nil)
+		result, err := GetComparisonResult(t.Context(), comparison, ComparisonOptions{})
 
 		// THEN
 		require.NoError(t, err)
 		snaps.MatchYAML(t, result)
+		failing := make(map[string]bool)
+		for _, module := range result.Modules {
+			failing[module.Name] = result.IsModuleFailing(module)
+		}
+		assert.Equal(t, map[string]bool{
+			"module_a": false,
+			"module_b": false,
+			"module_c": true,
+			"module_d": false,
+			"module_e": true,
+		}, failing)
 	})
+}
 
-	t.Run("works when missing modules are ignored", func(t *testing.T) {
+func TestGetComparisonResultWithAttributePaths(t *testing.T) {
+	t.Run("compares values nested in attributes, and complex values", func(t *testing.T) {
 		// GIVEN
-		sourceLabels := []string{"qa", "staging", "prod"}
+		comparison := domain.Comparison{
+			Name:          "test-comparison-complex",
+			AttributeKeys: []string{"instance_count", "tags.team", "tags[\"cost-center\"]", "node_pools"},
+			Sources: []domain.Source{
+				{
+					Path:  "testdata/complex/dev",
+					Label: "dev",
+				},
+				{
+					Path:  "testdata/complex/prod",
+					Label: "prod",
+				},
+			},
+		}
+
+		// WHEN
+		result, err := GetComparisonResult(t.Context(), comparison, ComparisonOptions{})
+
+		// THEN
+		require.NoError(t, err)
+		snaps.MatchYAML(t, result)
+	})
+
+	t.Run("omits values that don't have the path", func(t *testing.T) {
+		// GIVEN
+		comparison := domain.Comparison{
+			Name:          "test-comparison-complex",
+			AttributeKeys: []string{"node_pools[1].size"},
+			Sources: []domain.Source{
+				{
+					Path:  "testdata/complex/dev",
+					Label: "dev",
+				},
+				{
+					Path:  "testdata/complex/prod",
+					Label: "prod",
+				},
+			},
+		}
+
+		// WHEN
+		result, err := GetComparisonResult(t.Context(), comparison, ComparisonOptions{})
+
+		// THEN
+		require.NoError(t, err)
+		snaps.MatchYAML(t, result)
+	})
+}
+
+func TestGetComparisonResultForGitRefs(t *testing.T) {
+	valueRegex := regexp.MustCompile(`v?(\d+\.\d+\.\d+)`)
+
+	//-------------//
+	//  SUCCESSES  //
+	//-------------//
+
+	t.Run("works for sources at a git ref", func(t *testing.T) {
+		// GIVEN
+		setUpGitRepo(t)
+		comparison := domain.Comparison{
+			Name:          "test-comparison-git-ref",
+			AttributeKeys: []string{"source"},
+			Sources: []domain.Source{
+				{
+					Path:  "environments/prod",
+					Label: "prod-main",
+					Ref:   "main",
+				},
+				{
+					Path:  "environments/**/*.tf",
+					Label: "prod-head",
+					Ref:   "HEAD",
+				},
+				{
+					Path:  "environments/prod",
+					Label: "prod-worktree",
+				},
+			},
+		}
 
 		// WHEN
-		result, err := buildComparisonResult(store, sourceLabels, true, nil)
+		result, err := GetComparisonResult(t.Context(), comparison, ComparisonOptions{GlobalValueRegex: valueRegex})
 
 		// THEN
 		require.NoError(t, err)
 		snaps.MatchYAML(t, result)
 	})
+
+	//------------//
+	//  FAILURES  //
+	//------------//
+
+	t.Run("fails for an unknown ref", func(t *testing.T) {
+		// GIVEN
+		setUpGitRepo(t)
+		comparison := domain.Comparison{
+			Name:          "test-comparison-git-ref",
+			AttributeKeys: []string{"source"},
+			Sources: []domain.Source{
+				{
+					Path:  "environments/prod",
+					Label: "prod-main",
+					Ref:   "unknown",
+				},
+				{
+					Path:  "environments/prod",
+					Label: "prod-worktree",
+				},
+			},
+		}
+
+		// WHEN
+		_, err := GetComparisonResult(t.Context(), comparison, ComparisonOptions{GlobalValueRegex: valueRegex})
+
+		// THEN
+		require.ErrorIs(t, err, git.ErrCouldntListRef)
+	})
+
+	t.Run("fails when the path doesn't exist at the ref", func(t *testing.T) {
+		// GIVEN
+		setUpGitRepo(t)
+		comparison := domain.Comparison{
+			Name:          "test-comparison-git-ref",
+			AttributeKeys: []string{"source"},
+			Sources: []domain.Source{
+				{
+					Path:  "environments/staging",
+					Label: "staging-main",
+					Ref:   "main",
+				},
+				{
+					Path:  "environments/prod",
+					Label: "prod-worktree",
+				},
+			},
+		}
+
+		// WHEN
+		_, err := GetComparisonResult(t.Context(), comparison, ComparisonOptions{GlobalValueRegex: valueRegex})
+
+		// THEN
+		require.ErrorIs(t, err, hcl.ErrCouldntResolveSource)
+	})
+
+	t.Run("stops reading sources at a git ref once cancelled", func(t *testing.T) {
+		// GIVEN
+		setUpGitRepo(t)
+		comparison := domain.Comparison{
+			Name:          "test-comparison-git-ref",
+			AttributeKeys: []string{"source"},
+			Sources: []domain.Source{
+				{
+					Path:  "environments/prod",
+					Label: "prod-main",
+					Ref:   "main",
+				},
+				{
+					Path:  "environments/prod",
+					Label: "prod-worktree",
+				},
+			},
+		}
+		ctx, cancel := context.WithCancel(t.Context())
+		cancel()
+
+		// WHEN
+		_, err := GetComparisonResult(ctx, comparison, ComparisonOptions{GlobalValueRegex: valueRegex})
+
+		// THEN
+		require.ErrorIs(t, err, context.Canceled)
+	})
+}
+
+// setUpGitRepo creates a git repository with a commit on main, another one on
+// a feature branch, and uncommitted changes on top. The repository is used as
+// the working directory for the rest of the test.
+func TestGetComparisonResultWithGitDiffs(t *testing.T) {
+	valueRegex := regexp.MustCompile(`v?(\d+\.\d+\.\d+)`)
+
+	//-------------//
+	//  SUCCESSES  //
+	//-------------//
+
+	t.Run("built-in git provider works", func(t *testing.T) {
+		// GIVEN
+		repoPath := setUpModulesRepo(t)
+		comparison := domain.Comparison{
+			Name:          "test-comparison-git-diffs",
+			AttributeKeys: []string{"source"},
+			Sources: []domain.Source{
+				{
+					Path:  "testdata/environments/qa/main.tf",
+					Label: "qa",
+				},
+				{
+					Path:  "testdata/environments/prod/main.tf",
+					Label: "prod",
+				},
+			},
+			DiffCfgs: []domain.DiffConfig{{
+				BaseLabel: "prod",
+				HeadLabel: "qa",
+				Git: &domain.GitDiffConfig{
+					RepoPath:     repoPath,
+					RefTemplate:  "{{module}}-v{{value}}",
+					PathTemplate: "modules/{{module}}",
+				},
+			}},
+		}
+
+		// WHEN
+		result, err := GetComparisonResult(t.Context(), comparison, ComparisonOptions{
+			GlobalValueRegex: valueRegex,
+			IncludeDiffs:     true,
+		})
+
+		// THEN
+		require.NoError(t, err)
+		diffs := make(map[string]string)
+		for _, module := range result.Modules {
+			for _, diffResult := range module.DiffResults {
+				diffs[module.Name] = string(diffResult.Output)
+			}
+		}
+		snaps.MatchYAML(t, diffs)
+	})
+
+	t.Run("built-in git provider collects commit logs", func(t *testing.T) {
+		// GIVEN
+		repoPath := setUpModulesRepo(t)
+		comparison := domain.Comparison{
+			Name:          "test-comparison-git-diffs",
+			AttributeKeys: []string{"source"},
+			Sources: []domain.Source{
+				{
+					Path:  "testdata/environments/qa/main.tf",
+					Label: "qa",
+				},
+				{
+					Path:  "testdata/environments/prod/main.tf",
+					Label: "prod",
+				},
+			},
+			DiffCfgs: []domain.DiffConfig{{
+				BaseLabel: "prod",
+				HeadLabel: "qa",
+				Git: &domain.GitDiffConfig{
+					RepoPath:     repoPath,
+					RefTemplate:  "{{module}}-v{{value}}",
+					PathTemplate: "modules/{{module}}",
+					Log:          true,
+				},
+			}},
+		}
+
+		// WHEN
+		result, err := GetComparisonResult(t.Context(), comparison, ComparisonOptions{
+			GlobalValueRegex: valueRegex,
+			IncludeDiffs:     true,
+		})
+
+		// THEN
+		require.NoError(t, err)
+		// hashes depend on when the repository was created
+		subjects := make(map[string][]string)
+		for _, module := range result.Modules {
+			for _, diffResult := range module.DiffResults {
+				for _, commit := range diffResult.Commits {
+					assert.NotEmpty(t, commit.Hash)
+					subjects[module.Name] = append(subjects[module.Name], commit.Subject)
+				}
+			}
+		}
+		snaps.MatchYAML(t, subjects)
+	})
+
+	//------------//
+	//  FAILURES  //
+	//------------//
+
+	t.Run("built-in git provider fails for an unknown ref", func(t *testing.T) {
+		// GIVEN
+		repoPath := setUpModulesRepo(t)
+		comparison := domain.Comparison{
+			Name:          "test-comparison-git-diffs",
+			AttributeKeys: []string{"source"},
+			Sources: []domain.Source{
+				{
+					Path:  "testdata/environments/qa/main.tf",
+					Label: "qa",
+				},
+				{
+					Path:  "testdata/environments/prod/main.tf",
+					Label: "prod",
+				},
+			},
+			DiffCfgs: []domain.DiffConfig{{
+				BaseLabel: "prod",
+				HeadLabel: "qa",
+				Git: &domain.GitDiffConfig{
+					RepoPath:    repoPath,
+					RefTemplate: "release-{{value}}",
+				},
+			}},
+		}
+
+		// WHEN
+		_, err := GetComparisonResult(t.Context(), comparison, ComparisonOptions{
+			GlobalValueRegex: valueRegex,
+			IncludeDiffs:     true,
+		})
+
+		// THEN
+		require.ErrorIs(t, err, ErrCouldntComputeDiff)
+		assert.Contains(t, err.Error(), `module "module_a"`)
+		assert.Contains(t, err.Error(), "release-1.0.22..release-1.0.24")
+	})
+}
+
+// setUpModulesRepo creates a repository with module_a and module_b, where
+// every version of a module is tagged as <module>-v<version>.
+func setUpModulesRepo(t *testing.T) string {
+	t.Helper()
+
+	dir := t.TempDir()
+
+	runGit := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-c", "user.name=tflens", "-c", "user.email=tflens@example.com"}, args...)...)
+		cmd.Dir = dir
+		output, err := cmd.CombinedOutput()
+		require.NoErrorf(t, err, "git %v failed: %s", args, output)
+	}
+
+	writeModule := func(name, version string) {
+		t.Helper()
+		moduleDir := filepath.Join(dir, "modules", name)
+		require.NoError(t, os.MkdirAll(moduleDir, 0o755))
+		content := fmt.Sprintf("variable \"version\" {\n  default = %q\n}\n", version)
+		require.NoError(t, os.WriteFile(filepath.Join(moduleDir, "main.tf"), []byte(content), 0o644))
+	}
+
+	runGit("init", "--quiet", "--initial-branch", "main")
+	for _, release := range []struct{ module, version string }{
+		{"module_a", "1.0.22"},
+		{"module_b", "0.1.8"},
+		{"module_a", "1.0.24"},
+		{"module_b", "0.1.10"},
+	} {
+		writeModule(release.module, release.version)
+		runGit("add", ".")
+		runGit("commit", "--quiet", "-m", fmt.Sprintf("release %s %s", release.module, release.version))
+		runGit("tag", fmt.Sprintf("%s-v%s", release.module, release.version))
+	}
+
+	return dir
+}
+
+func setUpGitRepo(t *testing.T) {
+	t.Helper()
+
+	dir := t.TempDir()
+	t.Chdir(dir)
+
+	runGit := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-c", "user.name=tflens", "-c", "user.email=tflens@example.com"}, args...)...)
+		output, err := cmd.CombinedOutput()
+		require.NoErrorf(t, err, "git %v failed: %s", args, output)
+	}
+
+	writeModules := func(versions map[string]string) {
+		t.Helper()
+		var content strings.Builder
+		for _, name := range []string{"module_a", "module_b"} {
+			fmt.Fprintf(&content, `module %q {
+  source = "git@github.com:dhth/infrastructure//modules/%s?ref=v%s"
+}
+
+`, name, name, versions[name])
+		}
+		require.NoError(t, os.MkdirAll("environments/prod", 0o755))
+		require.NoError(t, os.WriteFile("environments/prod/main.tf", []byte(content.String()), 0o644))
+	}
+
+	runGit("init", "--quiet", "--initial-branch", "main")
+	writeModules(map[string]string{"module_a": "1.0.0", "module_b": "2.0.0"})
+	runGit("add", ".")
+	runGit("commit", "--quiet", "-m", "initial")
+
+	runGit("checkout", "--quiet", "-b", "feature")
+	writeModules(map[string]string{"module_a": "1.1.0", "module_b": "2.0.0"})
+	runGit("commit", "--quiet", "-am", "bump module_a")
+
+	writeModules(map[string]string{"module_a": "1.1.0", "module_b": "2.1.0"})
 }
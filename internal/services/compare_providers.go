@@ -0,0 +1,64 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/dhth/tflens/internal/domain"
+	"github.com/dhth/tflens/internal/hcl"
+)
+
+var ErrProvidersUnsupportedForTerragrunt = errors.New("comparing providers is not supported for terragrunt sources")
+
+// GetProvidersResult compares the version constraints of the providers, and of
+// terraform itself, required by a comparison's sources. Constraints are
+// compared as written; value regexes don't apply to them.
+func GetProvidersResult(ctx context.Context, comparison domain.Comparison, ignoreMissingProviders bool, jobs int) (domain.ComparisonResult, error) {
+	var zero domain.ComparisonResult
+	sourceLabels := make([]string, len(comparison.Sources))
+	for i, source := range comparison.Sources {
+		if source.Kind == domain.TerragruntSource {
+			return zero, fmt.Errorf("%w (source %q)", ErrProvidersUnsupportedForTerragrunt, source.Label)
+		}
+		sourceLabels[i] = source.Label
+	}
+
+	parsedSources := make([][]hcl.TFRequirement, len(comparison.Sources))
+	err := runConcurrently(len(comparison.Sources), jobs, func(i int) error {
+		source := comparison.Sources[i]
+		fsys, err := sourceFS(ctx, source)
+		if err != nil {
+			return err
+		}
+
+		requirements, err := hcl.ParseRequirements(fsys, source.Path)
+		if err != nil {
+			return err
+		}
+
+		parsedSources[i] = requirements
+		return nil
+	})
+	if err != nil {
+		return zero, err
+	}
+
+	store := newAttributeStore("version")
+	for i, source := range comparison.Sources {
+		for _, requirement := range parsedSources[i] {
+			store.add(hcl.TFModule{
+				Name:         requirement.Name,
+				Attribute:    requirement.Constraint,
+				RawAttribute: requirement.Constraint,
+				File:         requirement.File,
+			}, source.Label)
+		}
+	}
+
+	result := buildComparisonResult([]attributeStore{store}, sourceLabels, ignoreMissingProviders, comparison.SemverCfg, comparison.PromotionOrder)
+	result.Name = comparison.Name
+	result.Kind = domain.ProvidersResult
+
+	return result, nil
+}
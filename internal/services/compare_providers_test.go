@@ -0,0 +1,149 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/dhth/tflens/internal/domain"
+	"github.com/dhth/tflens/internal/hcl"
+	"github.com/gkampitakis/go-snaps/snaps"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetProvidersResult(t *testing.T) {
+	//-------------//
+	//  SUCCESSES  //
+	//-------------//
+
+	t.Run("works for various cases", func(t *testing.T) {
+		// GIVEN
+		comparison := domain.Comparison{
+			Name:          "test-comparison",
+			AttributeKeys: []string{"source"},
+			Sources: []domain.Source{
+				{
+					Path:  "testdata/providers/dev",
+					Label: "dev",
+				},
+				{
+					Path:  "testdata/providers/qa",
+					Label: "qa",
+				},
+				{
+					Path:  "testdata/providers/prod/versions.tf",
+					Label: "prod",
+				},
+			},
+		}
+
+		// WHEN
+		result, err := GetProvidersResult(t.Context(), comparison, false, 1)
+
+		// THEN
+		require.NoError(t, err)
+		snaps.MatchYAML(t, result)
+	})
+
+	t.Run("works when missing providers are to be ignored", func(t *testing.T) {
+		// GIVEN
+		comparison := domain.Comparison{
+			Name:          "test-comparison",
+			AttributeKeys: []string{"source"},
+			Sources: []domain.Source{
+				{
+					Path:  "testdata/providers/dev",
+					Label: "dev",
+				},
+				{
+					Path:  "testdata/providers/qa",
+					Label: "qa",
+				},
+			},
+		}
+
+		// WHEN
+		result, err := GetProvidersResult(t.Context(), comparison, true, 1)
+
+		// THEN
+		require.NoError(t, err)
+		snaps.MatchYAML(t, result)
+	})
+
+	t.Run("classifies drift between exact versions", func(t *testing.T) {
+		// GIVEN
+		comparison := domain.Comparison{
+			Name:          "test-comparison",
+			AttributeKeys: []string{"source"},
+			Sources: []domain.Source{
+				{
+					Path:  "testdata/providers/qa",
+					Label: "qa",
+				},
+				{
+					Path:  "testdata/providers/prod",
+					Label: "prod",
+				},
+			},
+			SemverCfg: &domain.SemverConfig{FailOn: domain.DriftMajor},
+		}
+
+		// WHEN
+		result, err := GetProvidersResult(t.Context(), comparison, true, 1)
+
+		// THEN
+		require.NoError(t, err)
+		snaps.MatchYAML(t, result)
+	})
+
+	//------------//
+	//  FAILURES  //
+	//------------//
+
+	t.Run("fails for terragrunt sources", func(t *testing.T) {
+		// GIVEN
+		comparison := domain.Comparison{
+			Name:          "test-comparison",
+			AttributeKeys: []string{"source"},
+			Sources: []domain.Source{
+				{
+					Path:  "testdata/terragrunt/qa",
+					Label: "qa",
+					Kind:  domain.TerragruntSource,
+				},
+				{
+					Path:  "testdata/providers/prod",
+					Label: "prod",
+				},
+			},
+		}
+
+		// WHEN
+		_, err := GetProvidersResult(t.Context(), comparison, false, 1)
+
+		// THEN
+		require.ErrorIs(t, err, ErrProvidersUnsupportedForTerragrunt)
+	})
+
+	t.Run("fails when a provider is required more than once", func(t *testing.T) {
+		// GIVEN
+		comparison := domain.Comparison{
+			Name:          "test-comparison",
+			AttributeKeys: []string{"source"},
+			Sources: []domain.Source{
+				{
+					Path:  "testdata/providers/duplicates",
+					Label: "dev",
+				},
+				{
+					Path:  "testdata/providers/prod",
+					Label: "prod",
+				},
+			},
+		}
+
+		// WHEN
+		_, err := GetProvidersResult(t.Context(), comparison, false, 1)
+
+		// THEN
+		require.ErrorIs(t, err, hcl.ErrDuplicateProvider)
+	})
+}
@@ -0,0 +1,42 @@
+package services
+
+import "sync"
+
+// runConcurrently calls fn for every index in [0, n), with at most jobs calls
+// running at a time. All calls are made even if some of them fail; the error
+// returned is the one for the lowest index, so that it doesn't depend on
+// scheduling.
+func runConcurrently(n, jobs int, fn func(i int) error) error {
+	if n == 0 {
+		return nil
+	}
+
+	jobs = max(1, min(jobs, n))
+
+	errs := make([]error, n)
+	indexes := make(chan int)
+
+	var wg sync.WaitGroup
+	for range jobs {
+		wg.Go(func() {
+			for i := range indexes {
+				errs[i] = fn(i)
+			}
+		})
+	}
+
+	for i := range n {
+		indexes <- i
+	}
+	close(indexes)
+
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,79 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunConcurrently(t *testing.T) {
+	//-------------//
+	//  SUCCESSES  //
+	//-------------//
+
+	t.Run("calls the function for every index without exceeding the limit", func(t *testing.T) {
+		// GIVEN
+		n := 50
+		jobs := 4
+		called := make([]bool, n)
+		var running, maxRunning atomic.Int32
+
+		// WHEN
+		err := runConcurrently(n, jobs, func(i int) error {
+			current := running.Add(1)
+			defer running.Add(-1)
+			for {
+				previous := maxRunning.Load()
+				if current <= previous || maxRunning.CompareAndSwap(previous, current) {
+					break
+				}
+			}
+
+			called[i] = true
+			return nil
+		})
+
+		// THEN
+		require.NoError(t, err)
+		for i, c := range called {
+			assert.Truef(t, c, "index %d wasn't processed", i)
+		}
+		assert.LessOrEqual(t, maxRunning.Load(), int32(jobs))
+	})
+
+	t.Run("works when there's nothing to do", func(t *testing.T) {
+		// GIVEN
+		// WHEN
+		err := runConcurrently(0, 4, func(_ int) error {
+			return errors.New("shouldn't be called")
+		})
+
+		// THEN
+		require.NoError(t, err)
+	})
+
+	//------------//
+	//  FAILURES  //
+	//------------//
+
+	t.Run("returns the error for the lowest index", func(t *testing.T) {
+		// GIVEN
+		errFailed := errors.New("failed")
+
+		// WHEN
+		err := runConcurrently(20, 8, func(i int) error {
+			if i%5 == 3 {
+				return fmt.Errorf("%w: %d", errFailed, i)
+			}
+			return nil
+		})
+
+		// THEN
+		require.ErrorIs(t, err, errFailed)
+		assert.Equal(t, "failed: 3", err.Error())
+	})
+}
@@ -0,0 +1,252 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"sort"
+
+	"github.com/dhth/tflens/internal/domain"
+	"github.com/dhth/tflens/internal/hcl"
+)
+
+var (
+	ErrLabelNotFound       = errors.New("label not found in comparison")
+	ErrCantSyncToRef       = errors.New("sources read from a git ref cannot be synced to")
+	ErrSameSyncLabels      = errors.New("labels to sync from and to need to be different")
+	ErrModuleNotFound      = errors.New("module not found")
+	ErrCouldntReadTFFile   = errors.New("couldn't read file")
+	ErrCouldntWriteTFFile  = errors.New("couldn't write file")
+	ErrUnexpectedSyncState = errors.New("unexpected state for sync")
+	ErrUnresolvedValue     = errors.New("value couldn't be resolved")
+	ErrCantSyncComputed    = errors.New("values computed from locals or variables cannot be synced to")
+	ErrCantSyncNestedValue = errors.New("values nested in attributes cannot be synced")
+	ErrCantSyncComplex     = errors.New("complex values cannot be synced")
+	ErrCantLocateValue     = errors.New("couldn't locate the value to replace")
+)
+
+// PlanSync determines the changes needed to have the modules in the source
+// labelled toLabel use the same values as the ones in the source labelled
+// fromLabel. If moduleNames is empty, all modules present in both sources
+// that are out of sync are considered. Only the comparison's primary attribute
+// is synced. No files are written.
+func PlanSync(
+	ctx context.Context,
+	comparison domain.Comparison,
+	globalValueRegex *regexp.Regexp,
+	fromLabel, toLabel string,
+	moduleNames []string,
+) (domain.SyncPlan, error) {
+	var zero domain.SyncPlan
+
+	if fromLabel == toLabel {
+		return zero, fmt.Errorf("%w: %q", ErrSameSyncLabels, fromLabel)
+	}
+
+	fromSource, err := findSource(comparison, fromLabel)
+	if err != nil {
+		return zero, err
+	}
+
+	toSource, err := findSource(comparison, toLabel)
+	if err != nil {
+		return zero, err
+	}
+
+	if toSource.Ref != "" {
+		return zero, fmt.Errorf("%w: %q (ref: %q)", ErrCantSyncToRef, toLabel, toSource.Ref)
+	}
+
+	attributeKey := comparison.AttributeKeys[0]
+	attributePath, err := domain.ParseAttributePath(attributeKey)
+	if err != nil {
+		return zero, err
+	}
+	if attributePath.IsNested() {
+		return zero, fmt.Errorf("%w: %q", ErrCantSyncNestedValue, attributeKey)
+	}
+
+	fromModules, err := parseSource(ctx, fromSource, []string{attributeKey}, sourceValueRegex(comparison, fromSource, globalValueRegex))
+	if err != nil {
+		return zero, err
+	}
+
+	toValueRegex := sourceValueRegex(comparison, toSource, globalValueRegex)
+	toModules, err := parseSource(ctx, toSource, []string{attributeKey}, toValueRegex)
+	if err != nil {
+		return zero, err
+	}
+
+	fromByName := make(map[string]hcl.TFModule, len(fromModules[0]))
+	for _, mod := range fromModules[0] {
+		fromByName[mod.Name] = mod
+	}
+
+	toByName := make(map[string]hcl.TFModule, len(toModules[0]))
+	for _, mod := range toModules[0] {
+		toByName[mod.Name] = mod
+	}
+
+	var selected []string
+	if len(moduleNames) > 0 {
+		for _, name := range moduleNames {
+			if _, ok := fromByName[name]; !ok {
+				return zero, fmt.Errorf("%w: %q in source %q", ErrModuleNotFound, name, fromLabel)
+			}
+			if _, ok := toByName[name]; !ok {
+				return zero, fmt.Errorf("%w: %q in source %q", ErrModuleNotFound, name, toLabel)
+			}
+			if !slices.Contains(selected, name) {
+				selected = append(selected, name)
+			}
+		}
+	} else {
+		for name := range fromByName {
+			if _, ok := toByName[name]; !ok {
+				continue
+			}
+			if slices.Contains(comparison.IgnoreModules, name) {
+				continue
+			}
+			selected = append(selected, name)
+		}
+	}
+	sort.Strings(selected)
+
+	plan := domain.SyncPlan{
+		FromLabel: fromLabel,
+		ToLabel:   toLabel,
+	}
+
+	//               file   module  raw value
+	edits := make(map[string]map[string]string)
+	for _, name := range selected {
+		from := fromByName[name]
+		to := toByName[name]
+
+		// modules are only skipped when they weren't explicitly asked for
+		if from.ResolveErr != nil {
+			if len(moduleNames) == 0 {
+				continue
+			}
+			return zero, fmt.Errorf("%w: module %q in source %q: %w", ErrUnresolvedValue, name, fromLabel, from.ResolveErr)
+		}
+
+		if from.Attribute == to.Attribute {
+			continue
+		}
+
+		if from.Complex || to.Complex {
+			if len(moduleNames) == 0 {
+				continue
+			}
+			return zero, fmt.Errorf("%w: module %q", ErrCantSyncComplex, name)
+		}
+
+		if to.ResolveErr != nil || to.Computed {
+			if len(moduleNames) == 0 {
+				continue
+			}
+			return zero, fmt.Errorf("%w: module %q in source %q; update the value where it's defined instead", ErrCantSyncComputed, name, toLabel)
+		}
+
+		newRaw, err := hcl.ReplaceValue(to.RawAttribute, from.Attribute, toValueRegex)
+		if err != nil {
+			return zero, fmt.Errorf("%w: module %q in source %q: %w", ErrCantLocateValue, name, toLabel, err)
+		}
+		if newRaw == to.RawAttribute {
+			continue
+		}
+
+		plan.Changes = append(plan.Changes, domain.SyncChange{
+			Module:   name,
+			File:     to.File,
+			OldValue: to.Attribute,
+			NewValue: from.Attribute,
+		})
+
+		fileEdits, ok := edits[to.File]
+		if !ok {
+			fileEdits = make(map[string]string)
+		}
+		fileEdits[name] = newRaw
+		edits[to.File] = fileEdits
+	}
+
+	files := make([]string, 0, len(edits))
+	for file := range edits {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+
+	for _, file := range files {
+		fileChange, err := rewriteFile(file, toSource.Kind, attributeKey, edits[file])
+		if err != nil {
+			return zero, err
+		}
+		plan.Files = append(plan.Files, fileChange)
+	}
+
+	return plan, nil
+}
+
+func ApplySyncPlan(plan domain.SyncPlan) error {
+	for _, file := range plan.Files {
+		info, err := os.Stat(file.Path)
+		if err != nil {
+			return fmt.Errorf("%w (%q): %w", ErrCouldntWriteTFFile, file.Path, err)
+		}
+
+		err = os.WriteFile(file.Path, file.After, info.Mode().Perm())
+		if err != nil {
+			return fmt.Errorf("%w (%q): %w", ErrCouldntWriteTFFile, file.Path, err)
+		}
+	}
+
+	return nil
+}
+
+func findSource(comparison domain.Comparison, label string) (domain.Source, error) {
+	for _, source := range comparison.Sources {
+		if source.Label == label {
+			return source, nil
+		}
+	}
+
+	return domain.Source{}, fmt.Errorf("%w: %q", ErrLabelNotFound, label)
+}
+
+func rewriteFile(path string, kind domain.SourceKind, attributeKey string, values map[string]string) (domain.FileChange, error) {
+	var zero domain.FileChange
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return zero, fmt.Errorf("%w (%q): %w", ErrCouldntReadTFFile, path, err)
+	}
+
+	var updated []byte
+	switch kind {
+	case domain.TerragruntSource:
+		// every terragrunt unit has its own file, so there's only one value
+		if len(values) != 1 {
+			return zero, fmt.Errorf("%w: %d terragrunt units in %q", ErrUnexpectedSyncState, len(values), path)
+		}
+		for _, value := range values {
+			updated, err = hcl.RewriteTerragruntAttribute(path, content, attributeKey, value)
+		}
+	default:
+		updated, err = hcl.RewriteModuleAttributes(path, content, attributeKey, values)
+	}
+	if err != nil {
+		return zero, err
+	}
+
+	return domain.FileChange{
+		Path:   path,
+		Before: content,
+		After:  updated,
+	}, nil
+}
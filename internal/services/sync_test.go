@@ -0,0 +1,287 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/dhth/tflens/internal/domain"
+	"github.com/dhth/tflens/internal/hcl"
+	"github.com/gkampitakis/go-snaps/snaps"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlanSync(t *testing.T) {
+	valueRegex := regexp.MustCompile(`v?(\d+\.\d+\.\d+)`)
+
+	comparison := domain.Comparison{
+		Name:          "test-comparison",
+		AttributeKeys: []string{"source"},
+		Sources: []domain.Source{
+			{
+				Path:  "testdata/environments/qa/main.tf",
+				Label: "qa",
+			},
+			{
+				Path:  "testdata/environments/prod/main.tf",
+				Label: "prod",
+			},
+		},
+	}
+
+	interpolatedComparison := domain.Comparison{
+		Name:          "test-comparison-interpolated",
+		AttributeKeys: []string{"source"},
+		Sources: []domain.Source{
+			{
+				Path:  "testdata/interpolated/dev",
+				Label: "dev",
+			},
+			{
+				Path:  "testdata/interpolated/prod",
+				Label: "prod",
+			},
+		},
+	}
+
+	//-------------//
+	//  SUCCESSES  //
+	//-------------//
+
+	t.Run("plans changes for all out-of-sync modules", func(t *testing.T) {
+		// GIVEN
+		// WHEN
+		plan, err := PlanSync(t.Context(), comparison, valueRegex, "qa", "prod", nil)
+
+		// THEN
+		require.NoError(t, err)
+		snaps.MatchYAML(t, plan.Changes)
+		require.Len(t, plan.Files, 1)
+		snaps.MatchSnapshot(t, string(plan.Files[0].After))
+	})
+
+	t.Run("plans changes only for the requested modules", func(t *testing.T) {
+		// GIVEN
+		// WHEN
+		plan, err := PlanSync(t.Context(), comparison, valueRegex, "qa", "prod", []string{"module_b"})
+
+		// THEN
+		require.NoError(t, err)
+		snaps.MatchYAML(t, plan.Changes)
+	})
+
+	t.Run("replaces the entire value when no regex applies", func(t *testing.T) {
+		// GIVEN
+		// WHEN
+		plan, err := PlanSync(t.Context(), comparison, nil, "qa", "prod", []string{"module_a"})
+
+		// THEN
+		require.NoError(t, err)
+		snaps.MatchYAML(t, plan.Changes)
+	})
+
+	t.Run("works for terragrunt sources", func(t *testing.T) {
+		// GIVEN
+		terragruntComparison := domain.Comparison{
+			Name:          "test-comparison-terragrunt",
+			AttributeKeys: []string{"source"},
+			Sources: []domain.Source{
+				{
+					Path:  "testdata/terragrunt/qa",
+					Label: "qa",
+					Kind:  domain.TerragruntSource,
+				},
+				{
+					Path:  "testdata/terragrunt/prod",
+					Label: "prod",
+					Kind:  domain.TerragruntSource,
+				},
+			},
+		}
+
+		// WHEN
+		plan, err := PlanSync(t.Context(), terragruntComparison, valueRegex, "qa", "prod", nil)
+
+		// THEN
+		require.NoError(t, err)
+		snaps.MatchYAML(t, plan.Changes)
+		require.Len(t, plan.Files, 1)
+		snaps.MatchSnapshot(t, string(plan.Files[0].After))
+	})
+
+	t.Run("skips modules whose values are computed or unresolved", func(t *testing.T) {
+		// GIVEN
+		// WHEN
+		plan, err := PlanSync(t.Context(), interpolatedComparison, valueRegex, "prod", "dev", nil)
+
+		// THEN
+		require.NoError(t, err)
+		assert.Empty(t, plan.Changes)
+		assert.Empty(t, plan.Files)
+	})
+
+	t.Run("applying a plan writes changes to disk", func(t *testing.T) {
+		// GIVEN
+		tempDir := t.TempDir()
+		qaPath := filepath.Join(tempDir, "qa.tf")
+		prodPath := filepath.Join(tempDir, "prod.tf")
+		copyFile(t, "testdata/environments/qa/main.tf", qaPath)
+		copyFile(t, "testdata/environments/prod/main.tf", prodPath)
+
+		tempComparison := domain.Comparison{
+			Name:          "test-comparison",
+			AttributeKeys: []string{"source"},
+			Sources: []domain.Source{
+				{Path: qaPath, Label: "qa"},
+				{Path: prodPath, Label: "prod"},
+			},
+		}
+		plan, err := PlanSync(t.Context(), tempComparison, valueRegex, "qa", "prod", nil)
+		require.NoError(t, err)
+
+		// WHEN
+		err = ApplySyncPlan(plan)
+
+		// THEN
+		require.NoError(t, err)
+		replan, err := PlanSync(t.Context(), tempComparison, valueRegex, "qa", "prod", nil)
+		require.NoError(t, err)
+		assert.Empty(t, replan.Changes)
+	})
+
+	//------------//
+	//  FAILURES  //
+	//------------//
+
+	t.Run("fails for unknown label", func(t *testing.T) {
+		// GIVEN
+		// WHEN
+		_, err := PlanSync(t.Context(), comparison, valueRegex, "qa", "staging", nil)
+
+		// THEN
+		require.ErrorIs(t, err, ErrLabelNotFound)
+	})
+
+	t.Run("fails when labels are the same", func(t *testing.T) {
+		// GIVEN
+		// WHEN
+		_, err := PlanSync(t.Context(), comparison, valueRegex, "qa", "qa", nil)
+
+		// THEN
+		require.ErrorIs(t, err, ErrSameSyncLabels)
+	})
+
+	t.Run("fails when a requested module is absent in the target source", func(t *testing.T) {
+		// GIVEN
+		// WHEN
+		_, err := PlanSync(t.Context(), comparison, valueRegex, "qa", "prod", []string{"module_e"})
+
+		// THEN
+		require.ErrorIs(t, err, ErrModuleNotFound)
+	})
+
+	t.Run("fails when a requested module's target value is computed", func(t *testing.T) {
+		// GIVEN
+		// WHEN
+		_, err := PlanSync(t.Context(), interpolatedComparison, valueRegex, "prod", "dev", []string{"module_b"})
+
+		// THEN
+		require.ErrorIs(t, err, ErrCantSyncComputed)
+	})
+
+	t.Run("fails when a requested module's source value is unresolved", func(t *testing.T) {
+		// GIVEN
+		// WHEN
+		_, err := PlanSync(t.Context(), interpolatedComparison, valueRegex, "dev", "prod", []string{"module_d"})
+
+		// THEN
+		require.ErrorIs(t, err, ErrUnresolvedValue)
+		assert.Contains(t, err.Error(), `local "module_d_version" is not declared`)
+	})
+
+	t.Run("fails for values nested in attributes", func(t *testing.T) {
+		// GIVEN
+		nestedComparison := domain.Comparison{
+			Name:          "test-comparison-complex",
+			AttributeKeys: []string{"tags.team"},
+			Sources: []domain.Source{
+				{
+					Path:  "testdata/complex/dev",
+					Label: "dev",
+				},
+				{
+					Path:  "testdata/complex/prod",
+					Label: "prod",
+				},
+			},
+		}
+
+		// WHEN
+		_, err := PlanSync(t.Context(), nestedComparison, nil, "dev", "prod", nil)
+
+		// THEN
+		require.ErrorIs(t, err, ErrCantSyncNestedValue)
+	})
+
+	t.Run("fails when the target's value regex doesn't match its value", func(t *testing.T) {
+		// GIVEN
+		mismatchedComparison := domain.Comparison{
+			Name:          "test-comparison-mismatched-regex",
+			AttributeKeys: []string{"source"},
+			Sources: []domain.Source{
+				{
+					Path:  "testdata/environments/qa/main.tf",
+					Label: "qa",
+				},
+				{
+					Path:       "testdata/environments/prod/main.tf",
+					Label:      "prod",
+					ValueRegex: regexp.MustCompile(`branch=(\S+)`),
+				},
+			},
+		}
+
+		// WHEN
+		_, err := PlanSync(t.Context(), mismatchedComparison, valueRegex, "qa", "prod", nil)
+
+		// THEN
+		require.ErrorIs(t, err, ErrCantLocateValue)
+		require.ErrorIs(t, err, hcl.ErrValueRegexDidntMatch)
+	})
+
+	t.Run("fails when a requested module's value is complex", func(t *testing.T) {
+		// GIVEN
+		complexComparison := domain.Comparison{
+			Name:          "test-comparison-complex",
+			AttributeKeys: []string{"node_pools"},
+			Sources: []domain.Source{
+				{
+					Path:  "testdata/complex/dev",
+					Label: "dev",
+				},
+				{
+					Path:  "testdata/complex/prod",
+					Label: "prod",
+				},
+			},
+		}
+
+		// WHEN
+		_, err := PlanSync(t.Context(), complexComparison, nil, "dev", "prod", []string{"module_a"})
+
+		// THEN
+		require.ErrorIs(t, err, ErrCantSyncComplex)
+	})
+}
+
+func copyFile(t *testing.T, src, dst string) {
+	t.Helper()
+
+	content, err := os.ReadFile(src)
+	require.NoError(t, err)
+
+	err = os.WriteFile(dst, content, 0o644)
+	require.NoError(t, err)
+}
@@ -0,0 +1,33 @@
+locals {
+  team = "platform"
+}
+
+module "module_a" {
+  source         = "git::https://github.com/example/modules.git//eks?ref=v1.2.0"
+  instance_count = 3
+
+  tags = {
+    team        = local.team
+    cost-center = "1234"
+  }
+
+  node_pools = [
+    {
+      name = "default"
+      size = 3
+    },
+    {
+      name = "spot"
+      size = 2
+    },
+  ]
+}
+
+module "module_b" {
+  source         = "git::https://github.com/example/modules.git//rds?ref=v0.4.0"
+  instance_count = 1
+
+  tags = {
+    team = "data"
+  }
+}
@@ -0,0 +1,25 @@
+module "module_a" {
+  source         = "git::https://github.com/example/modules.git//eks?ref=v1.2.0"
+  instance_count = 5
+
+  tags = {
+    team        = "platform"
+    cost-center = "5678"
+  }
+
+  node_pools = [
+    {
+      name = "default"
+      size = 5
+    },
+  ]
+}
+
+module "module_b" {
+  source         = "git::https://github.com/example/modules.git//rds?ref=v0.4.0"
+  instance_count = 1
+
+  tags = {
+    team = "data"
+  }
+}
@@ -0,0 +1,5 @@
+module "module_a" {
+  source = "git@github.com:dhth/infrastructure//modules/applications/module-a?ref=module-a-v1.0.24"
+  environment                  = var.environment
+  prefix                       = var.prefix
+}
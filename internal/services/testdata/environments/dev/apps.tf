@@ -0,0 +1,11 @@
+module "module_c" {
+  source = "git@github.com:dhth/infrastructure//modules/applications/module-c?ref=module-c-v0.1.0"
+  environment                  = var.environment
+  prefix                       = var.prefix
+}
+
+module "module_d" {
+  source = "git@github.com:dhth/infrastructure//modules/applications/module-d?ref=module-c-v0.2.1"
+  environment                  = var.environment
+  prefix                       = var.prefix
+}
@@ -0,0 +1 @@
+data "aws_caller_identity" "current" {}
@@ -0,0 +1,11 @@
+module "module_a" {
+  source = "git@github.com:dhth/infrastructure//modules/applications/module-a?ref=module-a-v1.0.24"
+  environment                  = var.environment
+  prefix                       = var.prefix
+}
+
+module "module_b" {
+  source = "git@github.com:dhth/infrastructure//modules/applications/module-b?ref=module-b-v0.1.10"
+  environment                  = var.environment
+  prefix                       = var.prefix
+}
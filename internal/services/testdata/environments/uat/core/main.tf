@@ -0,0 +1,11 @@
+module "module_a" {
+  source = "git@github.com:dhth/infrastructure//modules/applications/module-a?ref=module-a-v1.0.22"
+  environment                  = var.environment
+  prefix                       = var.prefix
+}
+
+module "module_b" {
+  source = "git@github.com:dhth/infrastructure//modules/applications/module-b?ref=module-b-v0.1.8"
+  environment                  = var.environment
+  prefix                       = var.prefix
+}
@@ -0,0 +1,20 @@
+module "module_a" {
+  source  = "git::https://github.com/example/modules.git//eks?ref=v1.3.0"
+  count   = 1
+  enabled = true # enabled everywhere
+
+  tags = {
+    team = "platform" // owning team
+    env  = "dev"
+  }
+}
+
+module "module_b" {
+  source         = "git::https://github.com/example/modules.git//rds?ref=v0.4.0"
+  instance_class = var.instance_class
+  multi_az       = false
+}
+
+module "module_c" {
+  source = "git::https://github.com/example/modules.git//sqs?ref=v0.1.0"
+}
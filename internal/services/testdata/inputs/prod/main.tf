@@ -0,0 +1,22 @@
+module "module_a" {
+  source = "git::https://github.com/example/modules.git//eks?ref=v1.2.0"
+  count  = 3
+  # enabled everywhere
+  enabled = true
+
+  tags = {
+    team = "platform"
+    env = "prod"
+  }
+}
+
+module "module_b" {
+  source = "git::https://github.com/example/modules.git//rds?ref=v0.4.0"
+  instance_class = var.instance_class
+  multi_az = true
+  backup_retention_days = 7
+}
+
+module "module_d" {
+  source = "git::https://github.com/example/modules.git//sns?ref=v0.2.0"
+}
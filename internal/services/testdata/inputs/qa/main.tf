@@ -0,0 +1,9 @@
+module "module_b" {
+  source         = "git::https://github.com/example/modules.git//rds?ref=v0.4.0"
+  instance_class = var.instance_class
+  multi_az       = false
+}
+
+module "module_d" {
+  source = "git::https://github.com/example/modules.git//sns?ref=v0.2.0"
+}
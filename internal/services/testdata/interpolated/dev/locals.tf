@@ -0,0 +1,9 @@
+locals {
+  repo             = "git@github.com:dhth/infrastructure"
+  module_a_version = "1.0.24"
+  module_b_ref     = "module-b-v${var.module_b_version}"
+
+  # these refer to each other
+  module_f_version = local.module_f_fallback
+  module_f_fallback = local.module_f_version
+}
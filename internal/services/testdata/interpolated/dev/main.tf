@@ -0,0 +1,29 @@
+module "module_a" {
+  source      = "${local.repo}//modules/applications/module-a?ref=module-a-v${local.module_a_version}"
+  environment = var.environment
+}
+
+module "module_b" {
+  source      = "${local.repo}//modules/applications/module-b?ref=${local.module_b_ref}"
+  environment = var.environment
+}
+
+module "module_c" {
+  source      = "${local.repo}//modules/applications/module-c?ref=module-c-v${var.module_c_version}"
+  environment = var.environment
+}
+
+module "module_d" {
+  source      = "${local.repo}//modules/applications/module-d?ref=module-d-v${local.module_d_version}"
+  environment = var.environment
+}
+
+module "module_e" {
+  source      = format("%s//modules/applications/module-e?ref=module-e-v%s", local.repo, trimprefix("v0.3.0", "v"))
+  environment = var.environment
+}
+
+module "module_f" {
+  source      = "${local.repo}//modules/applications/module-f?ref=module-f-v${local.module_f_version}"
+  environment = var.environment
+}
@@ -0,0 +1,13 @@
+variable "environment" {
+  type    = string
+  default = "dev"
+}
+
+variable "module_b_version" {
+  type    = string
+  default = "0.1.6"
+}
+
+variable "module_c_version" {
+  type = string
+}
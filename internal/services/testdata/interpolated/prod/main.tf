@@ -0,0 +1,29 @@
+module "module_a" {
+  source      = "git@github.com:dhth/infrastructure//modules/applications/module-a?ref=module-a-v1.0.24"
+  environment = var.environment
+}
+
+module "module_b" {
+  source      = "git@github.com:dhth/infrastructure//modules/applications/module-b?ref=module-b-v0.1.8"
+  environment = var.environment
+}
+
+module "module_c" {
+  source      = "git@github.com:dhth/infrastructure//modules/applications/module-c?ref=module-c-v0.1.0"
+  environment = var.environment
+}
+
+module "module_d" {
+  source      = "git@github.com:dhth/infrastructure//modules/applications/module-d?ref=module-d-v0.2.0"
+  environment = var.environment
+}
+
+module "module_e" {
+  source      = "git@github.com:dhth/infrastructure//modules/applications/module-e?ref=module-e-v0.3.0"
+  environment = var.environment
+}
+
+module "module_f" {
+  source      = "git@github.com:dhth/infrastructure//modules/applications/module-f?ref=module-f-v0.1.0"
+  environment = var.environment
+}
@@ -0,0 +1,7 @@
+terraform {
+  required_version = "< 2.0.0"
+}
+
+module "module_a" {
+  source = "git::https://github.com/example/modules.git//eks?ref=v1.2.0"
+}
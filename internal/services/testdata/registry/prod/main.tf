@@ -0,0 +1,22 @@
+module "module_a" {
+  source  = "terraform-aws-modules/vpc/aws"
+  version = "5.1.0"
+}
+
+module "module_b" {
+  source  = "terraform-aws-modules/eks/aws"
+  version = "20.0.0"
+}
+
+module "module_c" {
+  source  = "example-org/s3-bucket/aws"
+  version = "4.0.0"
+}
+
+module "module_d" {
+  source = "git::https://github.com/example/modules.git//iam?ref=v1.0.0"
+}
+
+module "module_e" {
+  source = "terraform-aws-modules/iam/aws"
+}
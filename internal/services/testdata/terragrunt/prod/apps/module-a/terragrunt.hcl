@@ -0,0 +1,11 @@
+include "root" {
+  path = find_in_parent_folders("root.hcl")
+}
+
+terraform {
+  source = "git@github.com:dhth/infrastructure//modules/applications/module-a?ref=module-a-v1.0.22"
+}
+
+inputs = {
+  environment = "prod"
+}
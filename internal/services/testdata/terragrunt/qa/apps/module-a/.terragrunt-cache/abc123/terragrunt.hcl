@@ -0,0 +1,3 @@
+terraform {
+  source = "git@github.com:dhth/infrastructure//modules/applications/module-a?ref=module-a-v0.0.1"
+}
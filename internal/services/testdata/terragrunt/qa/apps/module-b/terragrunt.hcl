@@ -0,0 +1,11 @@
+include "root" {
+  path = find_in_parent_folders("root.hcl")
+}
+
+terraform {
+  source = "git@github.com:dhth/infrastructure//modules/applications/module-b?ref=module-b-v0.1.10"
+}
+
+inputs = {
+  environment = "qa"
+}
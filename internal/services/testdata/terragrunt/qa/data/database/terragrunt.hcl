@@ -0,0 +1,11 @@
+include "root" {
+  path = find_in_parent_folders("root.hcl")
+}
+
+terraform {
+  source = "git@github.com:dhth/infrastructure//modules/data/database?ref=database-v2.0.0"
+}
+
+inputs = {
+  environment = "qa"
+}
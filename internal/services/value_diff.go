@@ -0,0 +1,122 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/dhth/tflens/internal/domain"
+)
+
+// diffComplexValues returns the leaves of an attribute's values that differ
+// between the labels that have a value; values that aren't complex are leaves
+// themselves. Paths start with the attribute key, and are in the order in
+// which they first appear in the values.
+func diffComplexValues(attributeKey string, values map[string]string, complex map[string]bool, labels []string) []domain.ValueDiff {
+	var paths []string
+	//              path   label  leaf
+	leaves := make(map[string]map[string]string)
+	addLeaf := func(path, label, leaf string) {
+		if _, ok := leaves[path]; !ok {
+			leaves[path] = make(map[string]string)
+			paths = append(paths, path)
+		}
+		leaves[path][label] = leaf
+	}
+
+	var labelsWithValues []string
+	for _, label := range labels {
+		value, ok := values[label]
+		if !ok {
+			continue
+		}
+		labelsWithValues = append(labelsWithValues, label)
+
+		if !complex[label] {
+			addLeaf(attributeKey, label, jsonLeaf(value))
+			continue
+		}
+
+		decoder := json.NewDecoder(bytes.NewReader([]byte(value)))
+		decoder.UseNumber()
+		var decoded any
+		if err := decoder.Decode(&decoded); err != nil {
+			addLeaf(attributeKey, label, value)
+			continue
+		}
+
+		walkJSON(attributeKey, decoded, func(path, leaf string) {
+			addLeaf(path, label, leaf)
+		})
+	}
+
+	if len(labelsWithValues) < 2 {
+		return nil
+	}
+
+	var diffs []domain.ValueDiff
+	for _, path := range paths {
+		pathValues := leaves[path]
+		differs := len(pathValues) < len(labelsWithValues)
+		for _, label := range labelsWithValues {
+			if pathValues[label] != pathValues[labelsWithValues[0]] {
+				differs = true
+				break
+			}
+		}
+
+		if differs {
+			diffs = append(diffs, domain.ValueDiff{Path: path, Values: pathValues})
+		}
+	}
+
+	return diffs
+}
+
+// walkJSON calls fn for every leaf of a decoded JSON value; empty objects and
+// arrays are leaves as well.
+func walkJSON(path string, value any, fn func(path, leaf string)) {
+	switch v := value.(type) {
+	case map[string]any:
+		if len(v) == 0 {
+			fn(path, "{}")
+			return
+		}
+
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			walkJSON(path+domain.KeyStep(key), v[key], fn)
+		}
+	case []any:
+		if len(v) == 0 {
+			fn(path, "[]")
+			return
+		}
+
+		for i, item := range v {
+			walkJSON(fmt.Sprintf("%s[%d]", path, i), item, fn)
+		}
+	default:
+		encoded, err := json.Marshal(v)
+		if err != nil {
+			fn(path, fmt.Sprintf("%v", v))
+			return
+		}
+		fn(path, string(encoded))
+	}
+}
+
+func jsonLeaf(value string) string {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return value
+	}
+
+	return string(encoded)
+}
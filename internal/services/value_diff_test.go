@@ -0,0 +1,78 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/dhth/tflens/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffComplexValues(t *testing.T) {
+	labels := []string{"dev", "staging", "prod"}
+	complex := map[string]bool{"dev": true, "staging": true, "prod": true}
+
+	t.Run("returns leaves that differ", func(t *testing.T) {
+		// GIVEN
+		values := map[string]string{
+			"dev":     `[{"name":"default","size":3},{"name":"spot","size":2}]`,
+			"staging": `[{"name":"default","size":3}]`,
+			"prod":    `[{"name":"default","size":5}]`,
+		}
+
+		// WHEN
+		diffs := diffComplexValues("node_pools", values, complex, labels)
+
+		// THEN
+		assert.Equal(t, []domain.ValueDiff{
+			{Path: "node_pools[0].size", Values: map[string]string{"dev": "3", "staging": "3", "prod": "5"}},
+			{Path: "node_pools[1].name", Values: map[string]string{"dev": `"spot"`}},
+			{Path: "node_pools[1].size", Values: map[string]string{"dev": "2"}},
+		}, diffs)
+	})
+
+	t.Run("quotes keys that aren't identifiers", func(t *testing.T) {
+		// GIVEN
+		values := map[string]string{
+			"dev":  `{"cost center":"1234","team":"platform"}`,
+			"prod": `{"cost center":"5678","team":"platform"}`,
+		}
+
+		// WHEN
+		diffs := diffComplexValues("tags", values, complex, labels)
+
+		// THEN
+		assert.Equal(t, []domain.ValueDiff{
+			{Path: `tags["cost center"]`, Values: map[string]string{"dev": `"1234"`, "prod": `"5678"`}},
+		}, diffs)
+	})
+
+	t.Run("treats values that aren't complex as leaves", func(t *testing.T) {
+		// GIVEN
+		values := map[string]string{
+			"dev":  `{"team":"platform"}`,
+			"prod": "platform",
+		}
+
+		// WHEN
+		diffs := diffComplexValues("tags", values, map[string]bool{"dev": true}, labels)
+
+		// THEN
+		assert.Equal(t, []domain.ValueDiff{
+			{Path: "tags.team", Values: map[string]string{"dev": `"platform"`}},
+			{Path: "tags", Values: map[string]string{"prod": `"platform"`}},
+		}, diffs)
+	})
+
+	t.Run("returns nothing for a single value", func(t *testing.T) {
+		// GIVEN
+		values := map[string]string{
+			"dev": `{"team":"platform"}`,
+		}
+
+		// WHEN
+		diffs := diffComplexValues("tags", values, complex, labels)
+
+		// THEN
+		assert.Empty(t, diffs)
+	})
+}
@@ -0,0 +1,33 @@
+package utils
+
+import (
+	"io/fs"
+	"os"
+)
+
+// FS is the set of read operations needed to resolve and parse sources. Paths
+// are OS paths, relative to the current working directory or absolute.
+type FS interface {
+	fs.StatFS
+	fs.ReadDirFS
+	fs.ReadFileFS
+}
+
+// OSFS reads from the working tree.
+type OSFS struct{}
+
+func (OSFS) Open(name string) (fs.File, error) {
+	return os.Open(name)
+}
+
+func (OSFS) Stat(name string) (fs.FileInfo, error) {
+	return os.Stat(name)
+}
+
+func (OSFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	return os.ReadDir(name)
+}
+
+func (OSFS) ReadFile(name string) ([]byte, error) {
+	return os.ReadFile(name)
+}
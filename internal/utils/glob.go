@@ -0,0 +1,167 @@
+package utils
+
+import (
+	"errors"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+const globStarStar = "**"
+
+func HasGlobMeta(pattern string) bool {
+	return strings.ContainsAny(pattern, `*?[`)
+}
+
+// Glob works like filepath.Glob, but additionally supports "**" as a path
+// segment, which matches zero or more directories. Hidden directories (like
+// .terraform and .git) are not descended into, unless a segment of the pattern
+// starting with "." matches them. A pattern whose base directory doesn't exist
+// matches nothing; other errors in reading the base are returned.
+func Glob(fsys FS, pattern string) ([]string, error) {
+	err := ValidateGlob(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	segments := strings.Split(filepath.ToSlash(filepath.Clean(pattern)), "/")
+	base, baseLen := globBase(segments)
+	remaining := segments[baseLen:]
+	// without "**", there's no need to look deeper than the pattern itself
+	maxDepth := -1
+	if !slices.Contains(remaining, globStarStar) {
+		maxDepth = len(remaining)
+	}
+
+	if _, err := fsys.Stat(base); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var matches []string
+	err = fs.WalkDir(fsys, base, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrPermission) {
+				return fs.SkipDir
+			}
+			return err
+		}
+
+		rel, err := filepath.Rel(base, filepath.FromSlash(p))
+		if err != nil {
+			return err
+		}
+		if rel == "." {
+			return nil
+		}
+
+		relSegments := strings.Split(filepath.ToSlash(rel), "/")
+		if d.IsDir() && strings.HasPrefix(d.Name(), ".") && !matchesExplicitly(remaining, d.Name()) {
+			return fs.SkipDir
+		}
+
+		if matchSegments(remaining, relSegments) {
+			matches = append(matches, filepath.FromSlash(p))
+		}
+
+		if d.IsDir() && maxDepth >= 0 && len(relSegments) >= maxDepth {
+			return fs.SkipDir
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	slices.Sort(matches)
+
+	return matches, nil
+}
+
+// ValidateGlob reports whether a pattern is malformed, without matching it
+// against any files.
+func ValidateGlob(pattern string) error {
+	for _, segment := range strings.Split(filepath.ToSlash(filepath.Clean(pattern)), "/") {
+		if segment == globStarStar {
+			continue
+		}
+		if _, err := path.Match(segment, ""); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// GlobBase returns the longest leading portion of a pattern that doesn't
+// contain any glob meta characters.
+func GlobBase(pattern string) string {
+	base, _ := globBase(strings.Split(filepath.ToSlash(filepath.Clean(pattern)), "/"))
+	return base
+}
+
+func globBase(segments []string) (string, int) {
+	var baseSegments []string
+	for _, segment := range segments {
+		if HasGlobMeta(segment) {
+			break
+		}
+		baseSegments = append(baseSegments, segment)
+	}
+
+	base := strings.Join(baseSegments, "/")
+	if base == "" {
+		if len(baseSegments) > 0 {
+			return "/", len(baseSegments)
+		}
+		return ".", 0
+	}
+
+	return filepath.FromSlash(base), len(baseSegments)
+}
+
+// matchesExplicitly reports whether any segment of a pattern that starts with
+// "." matches a name; like in shells, wildcards don't match a leading ".".
+func matchesExplicitly(pattern []string, name string) bool {
+	for _, segment := range pattern {
+		if !strings.HasPrefix(segment, ".") {
+			continue
+		}
+		if matched, err := path.Match(segment, name); err == nil && matched {
+			return true
+		}
+	}
+
+	return false
+}
+
+func matchSegments(pattern, name []string) bool {
+	if len(pattern) == 0 {
+		return len(name) == 0
+	}
+
+	if pattern[0] == globStarStar {
+		for i := 0; i <= len(name); i++ {
+			if matchSegments(pattern[1:], name[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if len(name) == 0 {
+		return false
+	}
+
+	matched, err := path.Match(pattern[0], name[0])
+	if err != nil || !matched {
+		return false
+	}
+
+	return matchSegments(pattern[1:], name[1:])
+}
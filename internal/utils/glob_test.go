@@ -0,0 +1,111 @@
+package utils
+
+import (
+	"io/fs"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// statErrFS fails every Stat call with err.
+type statErrFS struct {
+	fstest.MapFS
+	err error
+}
+
+func (f statErrFS) Stat(_ string) (fs.FileInfo, error) {
+	return nil, f.err
+}
+
+func TestGlob(t *testing.T) {
+	fsys := fstest.MapFS{
+		"envs/dev/main.tf":                                  {},
+		"envs/dev/apps/main.tf":                             {},
+		"envs/dev/apps/README.md":                           {},
+		"envs/dev/.terraform/modules/eks/main.tf":           {},
+		"envs/prod/main.tf":                                 {},
+		"envs/prod/.shared/main.tf":                         {},
+		"envs/prod/.terraform/modules/rds/nested/main.tf":   {},
+		"envs/prod/.terraform/modules/rds/nested/README.md": {},
+	}
+
+	//-------------//
+	//  SUCCESSES  //
+	//-------------//
+
+	t.Run("matches single segment wildcards", func(t *testing.T) {
+		// GIVEN
+		// WHEN
+		matches, err := Glob(fsys, "envs/*/main.tf")
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, []string{"envs/dev/main.tf", "envs/prod/main.tf"}, matches)
+	})
+
+	t.Run("matches any number of directories for **", func(t *testing.T) {
+		// GIVEN
+		// WHEN
+		matches, err := Glob(fsys, "envs/**/*.tf")
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, []string{"envs/dev/apps/main.tf", "envs/dev/main.tf", "envs/prod/main.tf"}, matches)
+	})
+
+	t.Run("doesn't descend into hidden directories", func(t *testing.T) {
+		// GIVEN
+		// WHEN
+		matches, err := Glob(fsys, "envs/**/modules/**/main.tf")
+
+		// THEN
+		require.NoError(t, err)
+		assert.Empty(t, matches)
+	})
+
+	t.Run("descends into hidden directories named by the pattern", func(t *testing.T) {
+		// GIVEN
+		// WHEN
+		matches, err := Glob(fsys, "envs/**/.shared/*.tf")
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, []string{"envs/prod/.shared/main.tf"}, matches)
+	})
+
+	t.Run("matches nothing when the base doesn't exist", func(t *testing.T) {
+		// GIVEN
+		// WHEN
+		matches, err := Glob(fsys, "environments/**/*.tf")
+
+		// THEN
+		require.NoError(t, err)
+		assert.Empty(t, matches)
+	})
+
+	//------------//
+	//  FAILURES  //
+	//------------//
+
+	t.Run("fails for a malformed pattern", func(t *testing.T) {
+		// GIVEN
+		// WHEN
+		_, err := Glob(fsys, "envs/[/*.tf")
+
+		// THEN
+		require.Error(t, err)
+	})
+
+	t.Run("fails when the base can't be read", func(t *testing.T) {
+		// GIVEN
+		errFS := statErrFS{MapFS: fsys, err: fs.ErrPermission}
+
+		// WHEN
+		_, err := Glob(errFS, "envs/**/*.tf")
+
+		// THEN
+		require.ErrorIs(t, err, fs.ErrPermission)
+	})
+}
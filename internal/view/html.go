@@ -8,6 +8,10 @@ import (
 	"html/template"
 	"time"
 
+	"github.com/alecthomas/chroma/v2"
+	chromahtml "github.com/alecthomas/chroma/v2/formatters/html"
+	"github.com/alecthomas/chroma/v2/lexers"
+	"github.com/alecthomas/chroma/v2/styles"
 	"github.com/dhth/tflens/internal/domain"
 )
 
@@ -18,45 +22,65 @@ var (
 	errCouldntParseCustomTemplate  = errors.New("couldn't parse custom template")
 	ErrCouldntParseBuiltInTemplate = errors.New("couldn't parse built-in template")
 	errCouldntPopulateTemplate     = errors.New("couldn't populate template")
+	errCouldntHighlightDiff        = errors.New("couldn't highlight diff")
 )
 
-func RenderHTML(result domain.ComparisonResult, config HTMLConfig, referenceTime time.Time) (string, error) {
-	htmlData := NewHTMLData(config.Title, referenceTime)
-	htmlData.Columns = append([]string{"module"}, result.SourceLabels...)
-	htmlData.Columns = append(htmlData.Columns, "in-sync")
+// diffFormatter emits CSS classes rather than inline styles, so that the
+// stylesheet for diffs (see diffCSS) is only included once per report; the
+// surrounding element is expected to have the "chroma" class.
+var diffFormatter = chromahtml.New(chromahtml.WithClasses(true), chromahtml.PreventSurroundingPre(true))
 
-	for _, moduleResult := range result.Modules {
-		row := HTMLRow{
-			Data:   []string{moduleResult.Name},
-			Status: moduleResult.Status.String(),
-		}
+// diffStyle colors added and removed lines the way the rest of the report
+// colors text, rather than highlighting their background.
+var diffStyle = func() *chroma.Style {
+	base := styles.Get("gruvbox")
+	style, err := base.Builder().
+		Add(chroma.GenericDeleted, "#fb4934").
+		Add(chroma.GenericInserted, "#b8bb26").
+		Build()
+	if err != nil {
+		return base
+	}
 
-		for _, label := range result.SourceLabels {
-			row.Data = append(row.Data, moduleResult.Values[label])
-		}
+	return style
+}()
 
-		row.Data = append(row.Data, moduleResult.Status.Symbol())
+func RenderHTML(results []domain.ComparisonResult, config HTMLConfig, referenceTime time.Time) (string, error) {
+	htmlData := NewHTMLData(config.Title, referenceTime)
+
+	var zero string
 
-		htmlData.Rows = append(htmlData.Rows, row)
+	for _, result := range results {
+		section, err := newHTMLSection(result)
+		if err != nil {
+			return zero, err
+		}
+		htmlData.Sections = append(htmlData.Sections, section)
+		htmlData.Diffs = append(htmlData.Diffs, section.Diffs...)
+		htmlData.Commits = append(htmlData.Commits, section.Commits...)
+	}
 
-		if moduleResult.DiffResult == nil {
-			continue
+	if len(htmlData.Diffs) > 0 {
+		css, err := diffCSS()
+		if err != nil {
+			return zero, err
 		}
+		htmlData.DiffCSS = css
+	}
 
-		htmlData.Diffs = append(htmlData.Diffs, HTMLDiff{
-			ModuleName: moduleResult.Name,
-			Output:     template.HTML(moduleResult.DiffResult.Output),
-			BaseLabel:  moduleResult.DiffResult.BaseLabel,
-			HeadLabel:  moduleResult.DiffResult.HeadLabel,
-			BaseRef:    moduleResult.DiffResult.BaseRef,
-			HeadRef:    moduleResult.DiffResult.HeadRef,
-		})
+	if len(htmlData.Sections) == 1 {
+		htmlData.Columns = htmlData.Sections[0].Columns
+		htmlData.ColumnGroups = htmlData.Sections[0].ColumnGroups
+		htmlData.Rows = htmlData.Sections[0].Rows
 	}
 
-	var tmpl *template.Template
-	var templErr error
+	return executeHTMLTemplate(htmlData, config)
+}
 
+func executeHTMLTemplate(htmlData HTMLData, config HTMLConfig) (string, error) {
 	var zero string
+	var tmpl *template.Template
+	var templErr error
 
 	if config.CustomTemplate != nil {
 		tmpl, templErr = template.New("custom").Parse(*config.CustomTemplate)
@@ -78,3 +102,210 @@ func RenderHTML(result domain.ComparisonResult, config HTMLConfig, referenceTime
 
 	return buf.String(), nil
 }
+
+func newHTMLSection(result domain.ComparisonResult) (HTMLSection, error) {
+	section := HTMLSection{
+		Name: result.Name,
+	}
+	columns := valueColumns(result)
+	section.Columns = []string{result.Kind.Noun()}
+	for _, column := range columns {
+		section.Columns = append(section.Columns, column.label)
+	}
+	if result.SemverCfg != nil {
+		section.Columns = append(section.Columns, "drift")
+	}
+	section.Columns = append(section.Columns, "in-sync")
+
+	if len(result.AttributeKeys) > 0 {
+		section.ColumnGroups = append(section.ColumnGroups, HTMLColumnGroup{Span: 1})
+		for _, attributeKey := range result.AttributeKeys {
+			section.ColumnGroups = append(section.ColumnGroups, HTMLColumnGroup{
+				Name: attributeKey,
+				Span: len(result.SourceLabels),
+			})
+		}
+		section.ColumnGroups = append(section.ColumnGroups, HTMLColumnGroup{Span: len(section.Columns) - len(columns) - 1})
+	}
+
+	for _, u := range unresolvedValues(result) {
+		section.Unresolved = append(section.Unresolved, HTMLUnresolvedValue{
+			ModuleName:   u.module,
+			AttributeKey: u.attributeKey,
+			Label:        u.label,
+			Reason:       u.reason,
+		})
+	}
+
+	for _, moduleResult := range result.Modules {
+		for _, valueDiff := range moduleResult.ValueDiffs {
+			htmlValueDiff := HTMLValueDiff{
+				ModuleName: moduleResult.Name,
+				Path:       valueDiff.Path,
+			}
+			for _, label := range result.SourceLabels {
+				htmlValueDiff.Values = append(htmlValueDiff.Values, HTMLLabelValue{
+					Label: label,
+					Value: valueDiff.Values[label],
+				})
+			}
+			section.ValueDiffs = append(section.ValueDiffs, htmlValueDiff)
+		}
+	}
+
+	for _, moduleResult := range result.Modules {
+		row := HTMLRow{
+			Data:   []string{moduleResult.Name},
+			Status: moduleResult.Status.String(),
+		}
+
+		for _, column := range columns {
+			row.Data = append(row.Data, valueCell(moduleResult, column, ""))
+		}
+
+		if result.SemverCfg != nil {
+			row.Data = append(row.Data, driftCell(moduleResult))
+		}
+
+		row.Data = append(row.Data, moduleResult.Status.Symbol())
+
+		section.Rows = append(section.Rows, row)
+
+		for _, diffResult := range moduleResult.DiffResults {
+			if len(diffResult.Commits) > 0 {
+				commitLog := HTMLCommitLog{
+					ModuleName: moduleResult.Name,
+					BaseLabel:  diffResult.BaseLabel,
+					HeadLabel:  diffResult.HeadLabel,
+					BaseRef:    diffResult.BaseRef,
+					HeadRef:    diffResult.HeadRef,
+				}
+				for _, commit := range diffResult.Commits {
+					commitLog.Commits = append(commitLog.Commits, HTMLCommit{
+						Hash:    commit.Hash,
+						Subject: commit.Subject,
+					})
+				}
+				section.Commits = append(section.Commits, commitLog)
+			}
+
+			if len(diffResult.Output) == 0 {
+				continue
+			}
+
+			output, err := diffOutputHTML(diffResult)
+			if err != nil {
+				return section, fmt.Errorf("%w for module %q: %w", errCouldntHighlightDiff, moduleResult.Name, err)
+			}
+
+			section.Diffs = append(section.Diffs, HTMLDiff{
+				ModuleName: moduleResult.Name,
+				Output:     output,
+				BaseLabel:  diffResult.BaseLabel,
+				HeadLabel:  diffResult.HeadLabel,
+				BaseRef:    diffResult.BaseRef,
+				HeadRef:    diffResult.HeadRef,
+			})
+		}
+
+		for _, diffErr := range moduleResult.DiffErrors {
+			section.Diffs = append(section.Diffs, HTMLDiff{
+				ModuleName:  moduleResult.Name,
+				BaseLabel:   diffErr.BaseLabel,
+				HeadLabel:   diffErr.HeadLabel,
+				BaseRef:     diffErr.BaseRef,
+				HeadRef:     diffErr.HeadRef,
+				Unavailable: true,
+				Error:       diffErrorDetails(diffErr),
+			})
+		}
+	}
+
+	return section, nil
+}
+
+// RenderInputsHTML renders module inputs using the same template as
+// comparison results; each argument gets a row, whose status is "in_sync" if
+// its values are the same across labels, and "out_of_sync" otherwise.
+func RenderInputsHTML(results []domain.InputsResult, config HTMLConfig, referenceTime time.Time) (string, error) {
+	htmlData := NewHTMLData(config.Title, referenceTime)
+
+	for _, result := range results {
+		htmlData.Sections = append(htmlData.Sections, newInputsHTMLSection(result))
+	}
+
+	if len(htmlData.Sections) == 1 {
+		htmlData.Columns = htmlData.Sections[0].Columns
+		htmlData.Rows = htmlData.Sections[0].Rows
+	}
+
+	return executeHTMLTemplate(htmlData, config)
+}
+
+func newInputsHTMLSection(result domain.InputsResult) HTMLSection {
+	section := HTMLSection{
+		Name:      result.Name,
+		AlignLeft: true,
+	}
+
+	section.Columns = append(section.Columns, "module", "argument")
+	section.Columns = append(section.Columns, result.SourceLabels...)
+	section.Columns = append(section.Columns, "status")
+
+	for _, module := range result.Modules {
+		for _, argument := range module.Arguments {
+			status := domain.StatusInSync
+			if argument.Status != domain.ArgumentSame {
+				status = domain.StatusOutOfSync
+			}
+
+			row := HTMLRow{
+				Data:   []string{module.Name, argument.Name},
+				Status: status.String(),
+			}
+			for _, label := range result.SourceLabels {
+				row.Data = append(row.Data, argumentCell(module, argument, label))
+			}
+			row.Data = append(row.Data, argument.Status.String())
+
+			section.Rows = append(section.Rows, row)
+		}
+	}
+
+	return section
+}
+
+// diffOutputHTML escapes and highlights a diff's output, unless its command was
+// configured to print pre-rendered HTML; only then is the output trusted.
+func diffOutputHTML(diffResult domain.DiffResult) (template.HTML, error) {
+	if diffResult.OutputIsHTML {
+		return template.HTML(diffResult.Output), nil
+	}
+
+	return highlightDiffHTML(diffResult.Output)
+}
+
+func highlightDiffHTML(diff []byte) (template.HTML, error) {
+	iterator, err := lexers.Get("diff").Tokenise(nil, string(diff))
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	err = diffFormatter.Format(&buf, diffStyle, iterator)
+	if err != nil {
+		return "", err
+	}
+
+	return template.HTML(buf.String()), nil
+}
+
+func diffCSS() (template.CSS, error) {
+	var buf bytes.Buffer
+	err := diffFormatter.WriteCSS(&buf, diffStyle)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", errCouldntHighlightDiff, err)
+	}
+
+	return template.CSS(buf.String()), nil
+}
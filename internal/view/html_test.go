@@ -83,7 +83,7 @@ Generated at {{.Timestamp}}
 		}
 
 		// WHEN
-		output, err := RenderHTML(result, config, referenceTime)
+		output, err := RenderHTML([]domain.ComparisonResult{result}, config, referenceTime)
 
 		// THEN
 		require.NoError(t, err)
@@ -172,8 +172,8 @@ index 9b4c764..a42b111 100644
 						"prod-us": "1.8.0",
 						"prod-eu": "1.8.0",
 					},
-					Status:     domain.StatusOutOfSync,
-					DiffResult: &moduleBDiffResult,
+					Status:      domain.StatusOutOfSync,
+					DiffResults: []domain.DiffResult{moduleBDiffResult},
 				},
 				{
 					Name: "module_c",
@@ -181,8 +181,8 @@ index 9b4c764..a42b111 100644
 						"dev":     "1.1.0",
 						"prod-us": "0.8.0",
 					},
-					Status:     domain.StatusOutOfSync,
-					DiffResult: &moduleCDiffResult,
+					Status:      domain.StatusOutOfSync,
+					DiffResults: []domain.DiffResult{moduleCDiffResult},
 				},
 			},
 		}
@@ -192,7 +192,570 @@ index 9b4c764..a42b111 100644
 		}
 
 		// WHEN
-		output, err := RenderHTML(result, config, referenceTime)
+		output, err := RenderHTML([]domain.ComparisonResult{result}, config, referenceTime)
+
+		// THEN
+		require.NoError(t, err)
+		snaps.MatchStandaloneSnapshot(t, output)
+	})
+
+	t.Run("built in template doesn't load external resources", func(t *testing.T) {
+		// GIVEN
+		result := domain.ComparisonResult{
+			Name:         "apps",
+			SourceLabels: []string{"dev", "prod"},
+			Modules: []domain.ModuleResult{
+				{
+					Name: "module_a",
+					Values: map[string]string{
+						"dev":  "1.1.0",
+						"prod": "1.0.0",
+					},
+					Status: domain.StatusOutOfSync,
+					DiffResults: []domain.DiffResult{{
+						Output:    []byte("--- a/main.tf\n+++ b/main.tf\n@@ -1 +1 @@\n-a = 1\n+a = 2\n"),
+						BaseLabel: "prod",
+						HeadLabel: "dev",
+						BaseRef:   "1.0.0",
+						HeadRef:   "1.1.0",
+					}},
+				},
+			},
+		}
+
+		// WHEN
+		output, err := RenderHTML([]domain.ComparisonResult{result}, HTMLConfig{Title: "report"}, referenceTime)
+
+		// THEN
+		require.NoError(t, err)
+		assert.NotContains(t, output, "<script src")
+		assert.NotContains(t, output, "<link rel=\"stylesheet\"")
+		assert.NotContains(t, output, "@import")
+		assert.Contains(t, output, `<span class="gi">+a = 2`)
+	})
+
+	t.Run("built in template escapes diff output", func(t *testing.T) {
+		// GIVEN
+		maliciousDiff := `--- a/main.tf
++++ b/main.tf
+@@ -1 +1 @@
+-description = "</code></pre><img src=x onerror=alert(1)>"
++description = "<script>alert(document.cookie)</script>"
+`
+		result := domain.ComparisonResult{
+			Name:         "apps",
+			SourceLabels: []string{"dev", "prod"},
+			Modules: []domain.ModuleResult{
+				{
+					Name: "module_a",
+					Values: map[string]string{
+						"dev":  "1.1.0",
+						"prod": "1.0.0",
+					},
+					Status: domain.StatusOutOfSync,
+					DiffResults: []domain.DiffResult{{
+						Output:    []byte(maliciousDiff),
+						BaseLabel: "prod",
+						HeadLabel: "dev",
+						BaseRef:   "1.0.0",
+						HeadRef:   "1.1.0",
+					}},
+				},
+			},
+		}
+
+		// WHEN
+		output, err := RenderHTML([]domain.ComparisonResult{result}, HTMLConfig{Title: "report"}, referenceTime)
+
+		// THEN
+		require.NoError(t, err)
+		assert.NotContains(t, output, "<script>alert")
+		assert.NotContains(t, output, "<img")
+		assert.Contains(t, output, "&lt;script&gt;alert(document.cookie)&lt;/script&gt;")
+		assert.Contains(t, output, "&lt;img src=x onerror=alert(1)&gt;")
+	})
+
+	t.Run("custom template escapes diff output", func(t *testing.T) {
+		// GIVEN
+		result := domain.ComparisonResult{
+			Name:         "apps",
+			SourceLabels: []string{"dev", "prod"},
+			Modules: []domain.ModuleResult{
+				{
+					Name: "module_a",
+					Values: map[string]string{
+						"dev":  "1.1.0",
+						"prod": "1.0.0",
+					},
+					Status: domain.StatusOutOfSync,
+					DiffResults: []domain.DiffResult{{
+						Output:    []byte("+<script>alert(1)</script>\n"),
+						BaseLabel: "prod",
+						HeadLabel: "dev",
+						BaseRef:   "1.0.0",
+						HeadRef:   "1.1.0",
+					}},
+				},
+			},
+		}
+		customDiffTemplate := `{{range .Diffs}}<pre>{{.Output}}</pre>{{end}}`
+
+		// WHEN
+		output, err := RenderHTML([]domain.ComparisonResult{result}, HTMLConfig{CustomTemplate: &customDiffTemplate}, referenceTime)
+
+		// THEN
+		require.NoError(t, err)
+		assert.NotContains(t, output, "<script>")
+		assert.Contains(t, output, "&lt;script&gt;alert(1)&lt;/script&gt;")
+	})
+
+	t.Run("built in template includes diff output that's html as is", func(t *testing.T) {
+		// GIVEN
+		result := domain.ComparisonResult{
+			Name:         "apps",
+			SourceLabels: []string{"dev", "prod"},
+			Modules: []domain.ModuleResult{
+				{
+					Name: "module_a",
+					Values: map[string]string{
+						"dev":  "1.1.0",
+						"prod": "1.0.0",
+					},
+					Status: domain.StatusOutOfSync,
+					DiffResults: []domain.DiffResult{{
+						Output:       []byte(`<span class="added">+a = 2</span>`),
+						BaseLabel:    "prod",
+						HeadLabel:    "dev",
+						BaseRef:      "1.0.0",
+						HeadRef:      "1.1.0",
+						OutputIsHTML: true,
+					}},
+				},
+			},
+		}
+
+		// WHEN
+		output, err := RenderHTML([]domain.ComparisonResult{result}, HTMLConfig{Title: "report"}, referenceTime)
+
+		// THEN
+		require.NoError(t, err)
+		assert.Contains(t, output, `<code class="chroma diff-output"><span class="added">+a = 2</span></code>`)
+	})
+
+	t.Run("works for built in template when values are unresolved", func(t *testing.T) {
+		// GIVEN
+		result := domain.ComparisonResult{
+			Name:         "apps",
+			SourceLabels: []string{"dev", "prod"},
+			Modules: []domain.ModuleResult{
+				{
+					Name: "module_a",
+					Values: map[string]string{
+						"prod": "1.0.0",
+					},
+					Status: domain.StatusOutOfSync,
+					Unresolved: map[string]string{
+						"dev": `couldn't resolve value: variable "module_a_version" has no default, and isn't set in any var file`,
+					},
+				},
+				{
+					Name: "module_b",
+					Values: map[string]string{
+						"dev":  "2.0.0",
+						"prod": "2.0.0",
+					},
+					Status: domain.StatusInSync,
+				},
+			},
+		}
+
+		config := HTMLConfig{
+			Title: "Test Comparison with unresolved values",
+		}
+
+		// WHEN
+		output, err := RenderHTML([]domain.ComparisonResult{result}, config, referenceTime)
+
+		// THEN
+		require.NoError(t, err)
+		snaps.MatchStandaloneSnapshot(t, output)
+	})
+
+	t.Run("works for built in template when multiple attributes are compared", func(t *testing.T) {
+		// GIVEN
+		result := domain.ComparisonResult{
+			Name:          "apps",
+			SourceLabels:  []string{"dev", "prod"},
+			AttributeKeys: []string{"version", "source"},
+			Modules: []domain.ModuleResult{
+				{
+					Name: "module_a",
+					Values: map[string]string{
+						"dev":  "5.1.0",
+						"prod": "5.1.0",
+					},
+					Status: domain.StatusOutOfSync,
+					Attributes: map[string]domain.AttributeValues{
+						"version": {
+							Values: map[string]string{
+								"dev":  "5.1.0",
+								"prod": "5.1.0",
+							},
+						},
+						"source": {
+							Values: map[string]string{
+								"dev":  "terraform-aws-modules/vpc/aws",
+								"prod": "example-org/vpc/aws",
+							},
+						},
+					},
+				},
+				{
+					Name: "module_b",
+					Values: map[string]string{
+						"prod": "20.0.0",
+					},
+					Status: domain.StatusOutOfSync,
+					Unresolved: map[string]string{
+						"dev": `couldn't resolve value: local "eks_version" is not declared`,
+					},
+					Attributes: map[string]domain.AttributeValues{
+						"version": {
+							Values: map[string]string{
+								"prod": "20.0.0",
+							},
+							Unresolved: map[string]string{
+								"dev": `couldn't resolve value: local "eks_version" is not declared`,
+							},
+						},
+						"source": {
+							Values: map[string]string{
+								"dev":  "terraform-aws-modules/eks/aws",
+								"prod": "terraform-aws-modules/eks/aws",
+							},
+						},
+					},
+				},
+				{
+					Name:   "module_c",
+					Values: map[string]string{},
+					Status: domain.StatusInSync,
+					Attributes: map[string]domain.AttributeValues{
+						"version": {
+							Values: map[string]string{},
+						},
+						"source": {
+							Values: map[string]string{
+								"dev":  "git::https://github.com/example/modules.git//iam?ref=v1.0.0",
+								"prod": "git::https://github.com/example/modules.git//iam?ref=v1.0.0",
+							},
+						},
+					},
+				},
+			},
+		}
+
+		config := HTMLConfig{
+			Title: "Test Comparison with multiple attributes",
+		}
+
+		// WHEN
+		output, err := RenderHTML([]domain.ComparisonResult{result}, config, referenceTime)
+
+		// THEN
+		require.NoError(t, err)
+		snaps.MatchStandaloneSnapshot(t, output)
+	})
+
+	t.Run("works for built in template when complex values differ", func(t *testing.T) {
+		// GIVEN
+		result := domain.ComparisonResult{
+			Name:         "apps",
+			SourceLabels: []string{"dev", "prod"},
+			Modules: []domain.ModuleResult{
+				{
+					Name: "module_a",
+					Values: map[string]string{
+						"dev":  `[{"name":"default","size":3},{"name":"spot","size":2}]`,
+						"prod": `[{"name":"default","size":5}]`,
+					},
+					Status: domain.StatusOutOfSync,
+					ValueDiffs: []domain.ValueDiff{
+						{
+							Path: "node_pools[0].size",
+							Values: map[string]string{
+								"dev":  "3",
+								"prod": "5",
+							},
+						},
+						{
+							Path: "node_pools[1].name",
+							Values: map[string]string{
+								"dev": `"spot"`,
+							},
+						},
+					},
+				},
+				{
+					Name: "module_b",
+					Values: map[string]string{
+						"dev":  `[{"name":"default","size":1}]`,
+						"prod": `[{"name":"default","size":1}]`,
+					},
+					Status: domain.StatusInSync,
+				},
+			},
+		}
+
+		config := HTMLConfig{
+			Title: "Test Comparison with complex values",
+		}
+
+		// WHEN
+		output, err := RenderHTML([]domain.ComparisonResult{result}, config, referenceTime)
+
+		// THEN
+		require.NoError(t, err)
+		snaps.MatchStandaloneSnapshot(t, output)
+	})
+
+	t.Run("works for built in template when diffs are unavailable", func(t *testing.T) {
+		// GIVEN
+		result := domain.ComparisonResult{
+			Name:         "apps",
+			SourceLabels: []string{"dev", "prod"},
+			Modules: []domain.ModuleResult{
+				{
+					Name: "module_a",
+					Values: map[string]string{
+						"dev":  "1.1.0",
+						"prod": "1.0.0",
+					},
+					Status: domain.StatusOutOfSync,
+					DiffErrors: []domain.DiffError{{
+						BaseLabel: "prod",
+						HeadLabel: "dev",
+						BaseRef:   "1.0.0",
+						HeadRef:   "1.1.0",
+						ExitCode:  128,
+						Message:   "command exited with non success exit code",
+						Stderr:    "fatal: ambiguous argument 'module-a-v1.0.0..module-a-v1.1.0': unknown revision",
+					}},
+				},
+				{
+					Name: "module_b",
+					Values: map[string]string{
+						"dev":  "2.1.0",
+						"prod": "2.0.0",
+					},
+					Status: domain.StatusOutOfSync,
+					DiffErrors: []domain.DiffError{{
+						BaseLabel: "prod",
+						HeadLabel: "dev",
+						BaseRef:   "2.0.0",
+						HeadRef:   "2.1.0",
+						ExitCode:  -1,
+						Message:   "diff command timed out after 30s",
+					}},
+				},
+			},
+		}
+
+		config := HTMLConfig{
+			Title: "Test Comparison with unavailable diffs",
+		}
+
+		// WHEN
+		output, err := RenderHTML([]domain.ComparisonResult{result}, config, referenceTime)
+
+		// THEN
+		require.NoError(t, err)
+		snaps.MatchStandaloneSnapshot(t, output)
+	})
+
+	t.Run("works for built in template when commit logs are present", func(t *testing.T) {
+		// GIVEN
+		result := domain.ComparisonResult{
+			Name:         "apps",
+			SourceLabels: []string{"dev", "prod"},
+			Modules: []domain.ModuleResult{
+				{
+					Name: "module_a",
+					Values: map[string]string{
+						"dev":  "1.1.0",
+						"prod": "1.0.0",
+					},
+					Status: domain.StatusOutOfSync,
+					DiffResults: []domain.DiffResult{{
+						Output: []byte(`diff --git a/main.tf b/main.tf
+--- a/main.tf
++++ b/main.tf
+@@ -1,2 +1,2 @@
+-  count = 1
++  count = 2
+`),
+						BaseLabel: "prod",
+						HeadLabel: "dev",
+						BaseRef:   "1.0.0",
+						HeadRef:   "1.1.0",
+						Commits: []domain.Commit{
+							{Hash: "a1b2c3d", Subject: "module_a: bump count"},
+							{Hash: "e4f5a6b", Subject: "module_a: fix <tags> in descriptions"},
+						},
+					}},
+				},
+				{
+					Name: "module_b",
+					Values: map[string]string{
+						"dev":  "2.1.0",
+						"prod": "2.0.0",
+					},
+					Status: domain.StatusOutOfSync,
+					DiffResults: []domain.DiffResult{{
+						BaseLabel: "prod",
+						HeadLabel: "dev",
+						BaseRef:   "2.0.0",
+						HeadRef:   "2.1.0",
+						Commits: []domain.Commit{
+							{Hash: "0f1e2d3", Subject: "module_b: update docs"},
+						},
+					}},
+				},
+			},
+		}
+
+		config := HTMLConfig{
+			Title: "Test Comparison with commit logs",
+		}
+
+		// WHEN
+		output, err := RenderHTML([]domain.ComparisonResult{result}, config, referenceTime)
+
+		// THEN
+		require.NoError(t, err)
+		snaps.MatchStandaloneSnapshot(t, output)
+	})
+
+	t.Run("works for built in template when multiple comparisons are present", func(t *testing.T) {
+		// GIVEN
+		results := []domain.ComparisonResult{
+			{
+				Name:         "apps",
+				SourceLabels: []string{"dev", "prod"},
+				Modules: []domain.ModuleResult{
+					{
+						Name: "module_a",
+						Values: map[string]string{
+							"dev":  "1.0.0",
+							"prod": "1.0.0",
+						},
+						Status: domain.StatusInSync,
+					},
+				},
+			},
+			{
+				Name:         "data",
+				SourceLabels: []string{"dev", "prod"},
+				Modules: []domain.ModuleResult{
+					{
+						Name: "module_b",
+						Values: map[string]string{
+							"dev":  "2.1.0",
+							"prod": "2.0.0",
+						},
+						Status: domain.StatusOutOfSync,
+						DiffResults: []domain.DiffResult{{
+							Output:    []byte("-  count = 1\n+  count = 2\n"),
+							BaseLabel: "prod",
+							HeadLabel: "dev",
+							BaseRef:   "2.0.0",
+							HeadRef:   "2.1.0",
+						}},
+					},
+				},
+			},
+		}
+
+		config := HTMLConfig{
+			Title: "Test Comparisons",
+		}
+
+		// WHEN
+		output, err := RenderHTML(results, config, referenceTime)
+
+		// THEN
+		require.NoError(t, err)
+		snaps.MatchStandaloneSnapshot(t, output)
+	})
+
+	t.Run("works for built in template when semver drift is classified", func(t *testing.T) {
+		// GIVEN
+		result := domain.ComparisonResult{
+			SourceLabels: []string{"dev", "prod"},
+			Modules: []domain.ModuleResult{
+				{
+					Name: "module_a",
+					Values: map[string]string{
+						"dev":  "1.0.0",
+						"prod": "1.0.0",
+					},
+					Status: domain.StatusInSync,
+				},
+				{
+					Name: "module_b",
+					Values: map[string]string{
+						"dev":  "2.0.1",
+						"prod": "2.0.0",
+					},
+					Status: domain.StatusOutOfSync,
+					Drift:  domain.DriftPatch,
+				},
+			},
+			SemverCfg: &domain.SemverConfig{FailOn: domain.DriftMinor},
+		}
+
+		config := HTMLConfig{
+			Title: "Test Comparison",
+		}
+
+		// WHEN
+		output, err := RenderHTML([]domain.ComparisonResult{result}, config, referenceTime)
+
+		// THEN
+		require.NoError(t, err)
+		snaps.MatchStandaloneSnapshot(t, output)
+	})
+
+	t.Run("works for built in template when modules are ahead of upstream", func(t *testing.T) {
+		// GIVEN
+		result := domain.ComparisonResult{
+			SourceLabels: []string{"dev", "prod"},
+			Modules: []domain.ModuleResult{
+				{
+					Name: "module_a",
+					Values: map[string]string{
+						"dev":  "1.1.0",
+						"prod": "1.0.0",
+					},
+					Status: domain.StatusOutOfSync,
+				},
+				{
+					Name: "module_b",
+					Values: map[string]string{
+						"dev":  "2.0.0",
+						"prod": "2.1.0",
+					},
+					Status: domain.StatusAheadOfUpstream,
+				},
+			},
+		}
+
+		config := HTMLConfig{
+			Title: "Test Comparison",
+		}
+
+		// WHEN
+		output, err := RenderHTML([]domain.ComparisonResult{result}, config, referenceTime)
 
 		// THEN
 		require.NoError(t, err)
@@ -231,7 +794,7 @@ index 9b4c764..a42b111 100644
 		}
 
 		// WHEN
-		output, err := RenderHTML(result, config, referenceTime)
+		output, err := RenderHTML([]domain.ComparisonResult{result}, config, referenceTime)
 
 		// THEN
 		require.NoError(t, err)
@@ -270,7 +833,7 @@ index 9b4c764..a42b111 100644
 		}
 
 		// WHEN
-		output, err := RenderHTML(result, config, referenceTime)
+		output, err := RenderHTML([]domain.ComparisonResult{result}, config, referenceTime)
 
 		// THEN
 		require.NoError(t, err)
@@ -307,7 +870,7 @@ index 9b4c764..a42b111 100644
 		}
 
 		// WHEN
-		output, err := RenderHTML(result, config, referenceTime)
+		output, err := RenderHTML([]domain.ComparisonResult{result}, config, referenceTime)
 
 		// THEN
 		require.NoError(t, err)
@@ -344,7 +907,7 @@ index 9b4c764..a42b111 100644
 		}
 
 		// WHEN
-		output, err := RenderHTML(result, config, referenceTime)
+		output, err := RenderHTML([]domain.ComparisonResult{result}, config, referenceTime)
 
 		// THEN
 		require.NoError(t, err)
@@ -388,7 +951,7 @@ index 9b4c764..a42b111 100644
 		}
 
 		// WHEN
-		output, err := RenderHTML(result, config, referenceTime)
+		output, err := RenderHTML([]domain.ComparisonResult{result}, config, referenceTime)
 
 		// THEN
 		require.NoError(t, err)
@@ -423,9 +986,28 @@ index 9b4c764..a42b111 100644
 		}
 
 		// WHEN
-		_, err := RenderHTML(result, config, referenceTime)
+		_, err := RenderHTML([]domain.ComparisonResult{result}, config, referenceTime)
 
 		// THEN
 		assert.ErrorIs(t, err, errCouldntParseCustomTemplate)
 	})
 }
+
+func TestRenderInputsHTML(t *testing.T) {
+	referenceTime := time.Date(2024, 1, 15, 14, 30, 0, 0, time.UTC)
+
+	t.Run("works for built in template", func(t *testing.T) {
+		// GIVEN
+		result := getInputsResult()
+		config := HTMLConfig{
+			Title: "inputs",
+		}
+
+		// WHEN
+		html, err := RenderInputsHTML([]domain.InputsResult{result}, config, referenceTime)
+
+		// THEN
+		require.NoError(t, err)
+		snaps.MatchStandaloneSnapshot(t, html)
+	})
+}
@@ -0,0 +1,263 @@
+package view
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/dhth/tflens/internal/domain"
+)
+
+// JSONSchemaVersion is bumped whenever a backwards incompatible change is made
+// to the JSON output. Adding new fields is not considered as such a change.
+const JSONSchemaVersion = 2
+
+// InputsJSONSchemaVersion is bumped whenever a backwards incompatible change is
+// made to the JSON output of module inputs. Adding new fields is not
+// considered as such a change.
+const InputsJSONSchemaVersion = 1
+
+var errCouldntRenderJSON = errors.New("couldn't render JSON")
+
+type jsonReport struct {
+	SchemaVersion int              `json:"schemaVersion"`
+	Comparisons   []jsonComparison `json:"comparisons"`
+}
+
+type jsonComparison struct {
+	Name string `json:"name"`
+	// only present for results whose entries aren't modules; "modules" then
+	// holds entries of this kind
+	Kind   string   `json:"kind,omitempty"`
+	Labels []string `json:"labels"`
+	// only present when more than one attribute is compared; values and
+	// unresolved in modules are then for the first one
+	AttributeKeys []string     `json:"attributeKeys,omitempty"`
+	Modules       []jsonModule `json:"modules"`
+}
+
+type jsonModule struct {
+	Name       string            `json:"name"`
+	Values     map[string]string `json:"values"`
+	Status     string            `json:"status"`
+	Drift      string            `json:"drift,omitempty"`
+	Unresolved map[string]string `json:"unresolved,omitempty"`
+	ValueDiffs []jsonValueDiff   `json:"valueDiffs,omitempty"`
+	// values of every attribute, by attribute key
+	Attributes map[string]jsonAttribute `json:"attributes,omitempty"`
+	Diffs      []jsonDiff               `json:"diffs,omitempty"`
+	DiffErrors []jsonDiffError          `json:"diffErrors,omitempty"`
+}
+
+type jsonValueDiff struct {
+	Path   string            `json:"path"`
+	Values map[string]string `json:"values"`
+}
+
+type jsonAttribute struct {
+	Values     map[string]string `json:"values"`
+	Unresolved map[string]string `json:"unresolved,omitempty"`
+}
+
+type jsonDiff struct {
+	BaseLabel string       `json:"baseLabel"`
+	HeadLabel string       `json:"headLabel"`
+	BaseRef   string       `json:"baseRef"`
+	HeadRef   string       `json:"headRef"`
+	Output    string       `json:"output"`
+	Commits   []jsonCommit `json:"commits,omitempty"`
+}
+
+type jsonCommit struct {
+	Hash    string `json:"hash"`
+	Subject string `json:"subject"`
+}
+
+type jsonDiffError struct {
+	BaseLabel string `json:"baseLabel"`
+	HeadLabel string `json:"headLabel"`
+	BaseRef   string `json:"baseRef"`
+	HeadRef   string `json:"headRef"`
+	ExitCode  int    `json:"exitCode"`
+	Message   string `json:"message"`
+	Stderr    string `json:"stderr,omitempty"`
+}
+
+type jsonInputsReport struct {
+	SchemaVersion int                    `json:"schemaVersion"`
+	Comparisons   []jsonInputsComparison `json:"comparisons"`
+}
+
+type jsonInputsComparison struct {
+	Name    string             `json:"name"`
+	Labels  []string           `json:"labels"`
+	Modules []jsonModuleInputs `json:"modules"`
+}
+
+type jsonModuleInputs struct {
+	Name      string         `json:"name"`
+	Labels    []string       `json:"labels"`
+	Arguments []jsonArgument `json:"arguments"`
+}
+
+type jsonArgument struct {
+	Name   string            `json:"name"`
+	Values map[string]string `json:"values"`
+	Status string            `json:"status"`
+}
+
+func RenderJSON(writer io.Writer, results []domain.ComparisonResult) error {
+	report := jsonReport{
+		SchemaVersion: JSONSchemaVersion,
+		Comparisons:   make([]jsonComparison, 0, len(results)),
+	}
+
+	for _, result := range results {
+		report.Comparisons = append(report.Comparisons, newJSONComparison(result))
+	}
+
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+	err := encoder.Encode(report)
+	if err != nil {
+		return fmt.Errorf("%w: %w", errCouldntRenderJSON, err)
+	}
+
+	return nil
+}
+
+func newJSONComparison(result domain.ComparisonResult) jsonComparison {
+	labels := make([]string, len(result.SourceLabels))
+	copy(labels, result.SourceLabels)
+
+	modules := make([]jsonModule, 0, len(result.Modules))
+	for _, moduleResult := range result.Modules {
+		values := make(map[string]string, len(moduleResult.Values))
+		for label, value := range moduleResult.Values {
+			values[label] = value
+		}
+
+		module := jsonModule{
+			Name:   moduleResult.Name,
+			Values: values,
+			Status: moduleResult.Status.String(),
+		}
+
+		if len(moduleResult.Unresolved) > 0 {
+			module.Unresolved = make(map[string]string, len(moduleResult.Unresolved))
+			for label, reason := range moduleResult.Unresolved {
+				module.Unresolved[label] = reason
+			}
+		}
+
+		for _, valueDiff := range moduleResult.ValueDiffs {
+			module.ValueDiffs = append(module.ValueDiffs, jsonValueDiff{
+				Path:   valueDiff.Path,
+				Values: valueDiff.Values,
+			})
+		}
+
+		if len(moduleResult.Attributes) > 0 {
+			module.Attributes = make(map[string]jsonAttribute, len(moduleResult.Attributes))
+			for attributeKey, attribute := range moduleResult.Attributes {
+				module.Attributes[attributeKey] = jsonAttribute{
+					Values:     attribute.Values,
+					Unresolved: attribute.Unresolved,
+				}
+			}
+		}
+
+		if moduleResult.Drift != domain.DriftNone {
+			module.Drift = moduleResult.Drift.String()
+		}
+
+		for _, diffResult := range moduleResult.DiffResults {
+			diff := jsonDiff{
+				BaseLabel: diffResult.BaseLabel,
+				HeadLabel: diffResult.HeadLabel,
+				BaseRef:   diffResult.BaseRef,
+				HeadRef:   diffResult.HeadRef,
+				Output:    string(diffResult.Output),
+			}
+			for _, commit := range diffResult.Commits {
+				diff.Commits = append(diff.Commits, jsonCommit{
+					Hash:    commit.Hash,
+					Subject: commit.Subject,
+				})
+			}
+			module.Diffs = append(module.Diffs, diff)
+		}
+
+		for _, diffErr := range moduleResult.DiffErrors {
+			module.DiffErrors = append(module.DiffErrors, jsonDiffError{
+				BaseLabel: diffErr.BaseLabel,
+				HeadLabel: diffErr.HeadLabel,
+				BaseRef:   diffErr.BaseRef,
+				HeadRef:   diffErr.HeadRef,
+				ExitCode:  diffErr.ExitCode,
+				Message:   diffErr.Message,
+				Stderr:    diffErr.Stderr,
+			})
+		}
+
+		modules = append(modules, module)
+	}
+
+	var kind string
+	if result.Kind != domain.ModulesResult {
+		kind = result.Kind.String()
+	}
+
+	return jsonComparison{
+		Name:          result.Name,
+		Kind:          kind,
+		Labels:        labels,
+		AttributeKeys: result.AttributeKeys,
+		Modules:       modules,
+	}
+}
+
+func RenderInputsJSON(writer io.Writer, results []domain.InputsResult) error {
+	report := jsonInputsReport{
+		SchemaVersion: InputsJSONSchemaVersion,
+		Comparisons:   make([]jsonInputsComparison, 0, len(results)),
+	}
+
+	for _, result := range results {
+		comparison := jsonInputsComparison{
+			Name:    result.Name,
+			Labels:  result.SourceLabels,
+			Modules: make([]jsonModuleInputs, 0, len(result.Modules)),
+		}
+
+		for _, module := range result.Modules {
+			jsonModule := jsonModuleInputs{
+				Name:      module.Name,
+				Labels:    module.Labels,
+				Arguments: make([]jsonArgument, 0, len(module.Arguments)),
+			}
+
+			for _, argument := range module.Arguments {
+				jsonModule.Arguments = append(jsonModule.Arguments, jsonArgument{
+					Name:   argument.Name,
+					Values: argument.Values,
+					Status: argument.Status.String(),
+				})
+			}
+
+			comparison.Modules = append(comparison.Modules, jsonModule)
+		}
+
+		report.Comparisons = append(report.Comparisons, comparison)
+	}
+
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+	err := encoder.Encode(report)
+	if err != nil {
+		return fmt.Errorf("%w: %w", errCouldntRenderJSON, err)
+	}
+
+	return nil
+}
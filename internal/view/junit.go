@@ -0,0 +1,222 @@
+package view
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+
+	"github.com/dhth/tflens/internal/domain"
+)
+
+var errCouldntRenderJUnit = errors.New("couldn't render JUnit XML")
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Skipped   int             `xml:"skipped,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	Skipped   *junitSkipped `xml:"skipped,omitempty"`
+	SystemOut *junitOutput  `xml:"system-out,omitempty"`
+	SystemErr *junitOutput  `xml:"system-err,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",cdata"`
+}
+
+type junitOutput struct {
+	Text string `xml:",cdata"`
+}
+
+type junitSkipped struct {
+	Message string `xml:"message,attr"`
+}
+
+func RenderJUnit(writer io.Writer, results []domain.ComparisonResult) error {
+	suites := junitTestSuites{
+		Name:   "tflens",
+		Suites: make([]junitTestSuite, 0, len(results)),
+	}
+
+	for _, result := range results {
+		suite := newJUnitTestSuite(result)
+		suites.Tests += suite.Tests
+		suites.Failures += suite.Failures
+		suites.Skipped += suite.Skipped
+		suites.Suites = append(suites.Suites, suite)
+	}
+
+	output, err := xml.MarshalIndent(suites, "", "  ")
+	if err != nil {
+		return fmt.Errorf("%w: %w", errCouldntRenderJUnit, err)
+	}
+
+	_, err = fmt.Fprintf(writer, "%s%s\n", xml.Header, output)
+	if err != nil {
+		return fmt.Errorf("%w: %w", errCouldntRenderJUnit, err)
+	}
+
+	return nil
+}
+
+func newJUnitTestSuite(result domain.ComparisonResult) junitTestSuite {
+	suite := junitTestSuite{
+		Name:      result.Name,
+		TestCases: make([]junitTestCase, 0, len(result.Modules)),
+	}
+
+	for _, module := range result.Modules {
+		testCase := junitTestCase{
+			Name:      module.Name,
+			ClassName: result.Name,
+		}
+
+		switch module.Status {
+		case domain.StatusOutOfSync:
+			if !result.IsModuleFailing(module) {
+				break
+			}
+
+			values := junitValues(valueColumns(result), module)
+			message := fmt.Sprintf("%s is out of sync", result.Kind.Noun())
+			if module.Drift != domain.DriftNone {
+				message = fmt.Sprintf("%s is out of sync (%s drift)", result.Kind.Noun(), module.Drift.String())
+			}
+
+			testCase.Failure = &junitFailure{
+				Message: junitText(fmt.Sprintf("%s: %s", message, strings.Join(values, ", "))),
+				Type:    module.Status.String(),
+				Text:    junitDetails(result, module),
+			}
+			suite.Failures++
+		case domain.StatusAheadOfUpstream:
+			values := junitValues(valueColumns(result), module)
+			testCase.Failure = &junitFailure{
+				Message: junitText(fmt.Sprintf("%s is ahead of upstream: %s", result.Kind.Noun(), strings.Join(values, ", "))),
+				Type:    module.Status.String(),
+				Text:    junitDetails(result, module),
+			}
+			suite.Failures++
+		case domain.StatusNotApplicable:
+			testCase.Skipped = &junitSkipped{
+				Message: fmt.Sprintf("%s doesn't have values in at least two sources", result.Kind.Noun()),
+			}
+			suite.Skipped++
+		}
+
+		var diffs []string
+		for _, diffResult := range module.DiffResults {
+			var commits strings.Builder
+			for _, commit := range diffResult.Commits {
+				fmt.Fprintf(&commits, "%s %s\n", commit.Hash, commit.Subject)
+			}
+			if commits.Len() > 0 {
+				commits.WriteString("\n")
+			}
+
+			diffs = append(diffs, fmt.Sprintf("%s..%s (%s..%s)\n\n%s%s",
+				diffResult.BaseLabel,
+				diffResult.HeadLabel,
+				diffResult.BaseRef,
+				diffResult.HeadRef,
+				commits.String(),
+				diffResult.Output,
+			))
+		}
+		if len(diffs) > 0 {
+			testCase.SystemOut = &junitOutput{
+				Text: junitText(strings.Join(diffs, "\n")),
+			}
+		}
+
+		var diffErrs []string
+		for _, diffErr := range module.DiffErrors {
+			diffErrs = append(diffErrs, fmt.Sprintf("%s..%s (%s..%s): diff unavailable\n\n%s",
+				diffErr.BaseLabel,
+				diffErr.HeadLabel,
+				diffErr.BaseRef,
+				diffErr.HeadRef,
+				diffErrorDetails(diffErr),
+			))
+		}
+		if len(diffErrs) > 0 {
+			testCase.SystemErr = &junitOutput{
+				Text: junitText(strings.Join(diffErrs, "\n\n")),
+			}
+		}
+
+		suite.TestCases = append(suite.TestCases, testCase)
+		suite.Tests++
+	}
+
+	return suite
+}
+
+func junitValues(columns []valueColumn, module domain.ModuleResult) []string {
+	result := make([]string, 0, len(columns))
+	for _, column := range columns {
+		result = append(result, fmt.Sprintf("%s=%s", column.name(), valueCell(module, column, "-")))
+	}
+
+	return result
+}
+
+// junitDetails lists a module's values, along with the reasons why any of
+// them couldn't be resolved, and the parts of complex values that differ.
+func junitDetails(result domain.ComparisonResult, module domain.ModuleResult) string {
+	columns := valueColumns(result)
+	lines := junitValues(columns, module)
+	for i, column := range columns {
+		if reason, ok := attributeValues(module, column.attributeKey).Unresolved[column.label]; ok {
+			lines[i] = fmt.Sprintf("%s (%s)", lines[i], reason)
+		}
+	}
+
+	if len(module.ValueDiffs) > 0 {
+		lines = append(lines, "", "value differences:")
+		for _, valueDiff := range module.ValueDiffs {
+			lines = append(lines, fmt.Sprintf("%s: %s", valueDiff.Path, strings.Join(valueDiffValues(result.SourceLabels, valueDiff), ", ")))
+		}
+	}
+
+	return junitText(strings.Join(lines, "\n"))
+}
+
+// junitText replaces characters that aren't allowed in XML documents (eg. the
+// escape character in ANSI color codes), as these would make the report
+// unparseable.
+func junitText(text string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r == '\t' || r == '\n' || r == '\r':
+			return r
+		case r < 0x20, r == 0xFFFE, r == 0xFFFF:
+			return unicode.ReplacementChar
+		case r >= 0xD800 && r <= 0xDFFF:
+			return unicode.ReplacementChar
+		default:
+			return r
+		}
+	}, text)
+}
@@ -0,0 +1,206 @@
+package view
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"strings"
+
+	"github.com/dhth/tflens/internal/domain"
+)
+
+var errCouldntRenderMarkdown = errors.New("couldn't render markdown")
+
+func RenderMarkdown(writer io.Writer, results []domain.ComparisonResult) error {
+	var output strings.Builder
+
+	for i, result := range results {
+		if len(results) > 1 {
+			if i > 0 {
+				output.WriteString("\n")
+			}
+			fmt.Fprintf(&output, "### %s\n\n", result.Name)
+		}
+
+		renderMarkdownResult(&output, result)
+	}
+
+	_, err := fmt.Fprint(writer, output.String())
+	if err != nil {
+		return fmt.Errorf("%w: %w", errCouldntRenderMarkdown, err)
+	}
+
+	return nil
+}
+
+func renderMarkdownResult(output *strings.Builder, result domain.ComparisonResult) {
+	columns := valueColumns(result)
+	headers := make([]string, 0, len(columns)+3)
+	headers = append(headers, result.Kind.Noun())
+	for _, column := range columns {
+		headers = append(headers, column.name())
+	}
+	if result.SemverCfg != nil {
+		headers = append(headers, "drift")
+	}
+	headers = append(headers, "in-sync")
+
+	alignments := make([]string, 0, len(headers))
+	alignments = append(alignments, ":---")
+	for range headers[1 : len(headers)-1] {
+		alignments = append(alignments, "---:")
+	}
+	alignments = append(alignments, ":---:")
+
+	writeMarkdownRow(output, headers)
+	writeMarkdownRow(output, alignments)
+
+	for _, module := range result.Modules {
+		row := make([]string, 0, len(headers))
+		row = append(row, markdownCell(module.Name, module.Status))
+
+		for _, column := range columns {
+			row = append(row, markdownCell(valueCell(module, column, "-"), module.Status))
+		}
+
+		if result.SemverCfg != nil {
+			row = append(row, driftCell(module))
+		}
+
+		row = append(row, module.Status.Symbol())
+		writeMarkdownRow(output, row)
+	}
+
+	if unresolved := unresolvedValues(result); len(unresolved) > 0 {
+		output.WriteString("\nUnresolved values:\n\n")
+		for _, u := range unresolved {
+			fmt.Fprintf(output, "- %s (%s): %s\n",
+				html.EscapeString(u.module),
+				html.EscapeString(u.where()),
+				html.EscapeString(u.reason),
+			)
+		}
+	}
+
+	if hasValueDiffs(result) {
+		output.WriteString("\nValue differences:\n\n")
+
+		headers := append([]string{"module", "path"}, result.SourceLabels...)
+		alignments := []string{":---", ":---"}
+		for range result.SourceLabels {
+			alignments = append(alignments, "---:")
+		}
+		writeMarkdownRow(output, headers)
+		writeMarkdownRow(output, alignments)
+
+		for _, module := range result.Modules {
+			for _, valueDiff := range module.ValueDiffs {
+				row := []string{markdownCell(module.Name, domain.StatusInSync), markdownCell(valueDiff.Path, domain.StatusInSync)}
+				for _, label := range result.SourceLabels {
+					value, ok := valueDiff.Values[label]
+					if !ok {
+						value = "-"
+					}
+					row = append(row, markdownCell(value, domain.StatusInSync))
+				}
+				writeMarkdownRow(output, row)
+			}
+		}
+	}
+
+	for _, module := range result.Modules {
+		for _, diffResult := range module.DiffResults {
+			var blocks []string
+			if len(diffResult.Commits) > 0 {
+				var commits strings.Builder
+				for i, commit := range diffResult.Commits {
+					if i > 0 {
+						commits.WriteString("\n")
+					}
+					fmt.Fprintf(&commits, "- `%s` %s", commit.Hash, html.EscapeString(commit.Subject))
+				}
+				blocks = append(blocks, commits.String())
+			}
+
+			if len(diffResult.Output) > 0 {
+				diff := string(diffResult.Output)
+				fence := markdownFence(diff)
+				blocks = append(blocks, fmt.Sprintf("%sdiff\n%s\n%s", fence, strings.TrimRight(diff, "\n"), fence))
+			}
+
+			fmt.Fprintf(output, `
+<details>
+<summary>%s %s..%s (%s..%s)</summary>
+
+%s
+
+</details>
+`,
+				html.EscapeString(module.Name),
+				html.EscapeString(diffResult.BaseLabel),
+				html.EscapeString(diffResult.HeadLabel),
+				html.EscapeString(diffResult.BaseRef),
+				html.EscapeString(diffResult.HeadRef),
+				strings.Join(blocks, "\n\n"),
+			)
+		}
+
+		for _, diffErr := range module.DiffErrors {
+			details := diffErrorDetails(diffErr)
+			fence := markdownFence(details)
+
+			fmt.Fprintf(output, `
+<details>
+<summary>%s %s..%s (%s..%s): diff unavailable</summary>
+
+%s
+%s
+%s
+
+</details>
+`,
+				html.EscapeString(module.Name),
+				html.EscapeString(diffErr.BaseLabel),
+				html.EscapeString(diffErr.HeadLabel),
+				html.EscapeString(diffErr.BaseRef),
+				html.EscapeString(diffErr.HeadRef),
+				fence,
+				details,
+				fence,
+			)
+		}
+	}
+}
+
+func writeMarkdownRow(builder *strings.Builder, cells []string) {
+	builder.WriteString("| ")
+	builder.WriteString(strings.Join(cells, " | "))
+	builder.WriteString(" |\n")
+}
+
+func markdownCell(value string, status domain.ModuleStatus) string {
+	escaped := strings.ReplaceAll(html.EscapeString(value), "|", `\|`)
+	if status == domain.StatusOutOfSync || status == domain.StatusAheadOfUpstream {
+		return fmt.Sprintf("**%s**", escaped)
+	}
+
+	return escaped
+}
+
+// markdownFence returns a code fence that's longer than any run of backticks
+// in content, so that the content can't terminate the code block early.
+func markdownFence(content string) string {
+	longestRun := 0
+	currentRun := 0
+	for _, r := range content {
+		if r == '`' {
+			currentRun++
+			longestRun = max(longestRun, currentRun)
+		} else {
+			currentRun = 0
+		}
+	}
+
+	return strings.Repeat("`", max(3, longestRun+1))
+}
@@ -0,0 +1,268 @@
+package view
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/dhth/tflens/internal/domain"
+	"github.com/gkampitakis/go-snaps/snaps"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderMarkdown(t *testing.T) {
+	t.Run("works for all in-sync modules", func(t *testing.T) {
+		// GIVEN
+		result := domain.ComparisonResult{
+			Name:         "apps",
+			SourceLabels: []string{"dev", "prod-us", "prod-eu"},
+			Modules: []domain.ModuleResult{
+				{
+					Name: "module_a",
+					Values: map[string]string{
+						"dev":     "1.0.0",
+						"prod-us": "1.0.0",
+						"prod-eu": "1.0.0",
+					},
+					Status: domain.StatusInSync,
+				},
+				{
+					Name: "module_b",
+					Values: map[string]string{
+						"dev": "2.0.0",
+					},
+					Status: domain.StatusNotApplicable,
+				},
+			},
+		}
+
+		var buf bytes.Buffer
+
+		// WHEN
+		err := RenderMarkdown(&buf, []domain.ComparisonResult{result})
+
+		// THEN
+		require.NoError(t, err)
+		snaps.MatchSnapshot(t, buf.String())
+	})
+
+	t.Run("works when values are unresolved", func(t *testing.T) {
+		// GIVEN
+		result := domain.ComparisonResult{
+			Name:         "apps",
+			SourceLabels: []string{"dev", "prod"},
+			Modules: []domain.ModuleResult{
+				{
+					Name: "module_a",
+					Values: map[string]string{
+						"prod": "1.0.0",
+					},
+					Status: domain.StatusOutOfSync,
+					Unresolved: map[string]string{
+						"dev": `couldn't resolve value: variable "module_a_version" has no default, and isn't set in any var file`,
+					},
+				},
+				{
+					Name: "module_b",
+					Values: map[string]string{
+						"dev":  "2.0.0",
+						"prod": "2.0.0",
+					},
+					Status: domain.StatusInSync,
+				},
+			},
+		}
+
+		var buf bytes.Buffer
+
+		// WHEN
+		err := RenderMarkdown(&buf, []domain.ComparisonResult{result})
+
+		// THEN
+		require.NoError(t, err)
+		snaps.MatchSnapshot(t, buf.String())
+	})
+
+	t.Run("works when multiple attributes are compared", func(t *testing.T) {
+		// GIVEN
+		result := domain.ComparisonResult{
+			Name:          "apps",
+			SourceLabels:  []string{"dev", "prod"},
+			AttributeKeys: []string{"version", "source"},
+			Modules: []domain.ModuleResult{
+				{
+					Name: "module_a",
+					Values: map[string]string{
+						"dev":  "5.1.0",
+						"prod": "5.1.0",
+					},
+					Status: domain.StatusOutOfSync,
+					Attributes: map[string]domain.AttributeValues{
+						"version": {
+							Values: map[string]string{
+								"dev":  "5.1.0",
+								"prod": "5.1.0",
+							},
+						},
+						"source": {
+							Values: map[string]string{
+								"dev":  "terraform-aws-modules/vpc/aws",
+								"prod": "example-org/vpc/aws",
+							},
+						},
+					},
+				},
+				{
+					Name: "module_b",
+					Values: map[string]string{
+						"prod": "20.0.0",
+					},
+					Status: domain.StatusOutOfSync,
+					Unresolved: map[string]string{
+						"dev": `couldn't resolve value: local "eks_version" is not declared`,
+					},
+					Attributes: map[string]domain.AttributeValues{
+						"version": {
+							Values: map[string]string{
+								"prod": "20.0.0",
+							},
+							Unresolved: map[string]string{
+								"dev": `couldn't resolve value: local "eks_version" is not declared`,
+							},
+						},
+						"source": {
+							Values: map[string]string{
+								"dev":  "terraform-aws-modules/eks/aws",
+								"prod": "terraform-aws-modules/eks/aws",
+							},
+						},
+					},
+				},
+				{
+					Name:   "module_c",
+					Values: map[string]string{},
+					Status: domain.StatusInSync,
+					Attributes: map[string]domain.AttributeValues{
+						"version": {
+							Values: map[string]string{},
+						},
+						"source": {
+							Values: map[string]string{
+								"dev":  "git::https://github.com/example/modules.git//iam?ref=v1.0.0",
+								"prod": "git::https://github.com/example/modules.git//iam?ref=v1.0.0",
+							},
+						},
+					},
+				},
+			},
+		}
+
+		var buf bytes.Buffer
+
+		// WHEN
+		err := RenderMarkdown(&buf, []domain.ComparisonResult{result})
+
+		// THEN
+		require.NoError(t, err)
+		snaps.MatchSnapshot(t, buf.String())
+	})
+
+	t.Run("works when complex values differ", func(t *testing.T) {
+		// GIVEN
+		result := domain.ComparisonResult{
+			Name:         "apps",
+			SourceLabels: []string{"dev", "prod"},
+			Modules: []domain.ModuleResult{
+				{
+					Name: "module_a",
+					Values: map[string]string{
+						"dev":  `[{"name":"default","size":3},{"name":"spot","size":2}]`,
+						"prod": `[{"name":"default","size":5}]`,
+					},
+					Status: domain.StatusOutOfSync,
+					ValueDiffs: []domain.ValueDiff{
+						{
+							Path: "node_pools[0].size",
+							Values: map[string]string{
+								"dev":  "3",
+								"prod": "5",
+							},
+						},
+						{
+							Path: "node_pools[1].name",
+							Values: map[string]string{
+								"dev": `"spot"`,
+							},
+						},
+					},
+				},
+				{
+					Name: "module_b",
+					Values: map[string]string{
+						"dev":  `[{"name":"default","size":1}]`,
+						"prod": `[{"name":"default","size":1}]`,
+					},
+					Status: domain.StatusInSync,
+				},
+			},
+		}
+
+		var buf bytes.Buffer
+
+		// WHEN
+		err := RenderMarkdown(&buf, []domain.ComparisonResult{result})
+
+		// THEN
+		require.NoError(t, err)
+		snaps.MatchSnapshot(t, buf.String())
+	})
+
+	t.Run("works when modules are out-of-sync and diffs are present", func(t *testing.T) {
+		// GIVEN
+		result := domain.ComparisonResult{
+			Name:         "apps",
+			SourceLabels: []string{"dev", "prod-us", "prod-eu"},
+			Modules: []domain.ModuleResult{
+				{
+					Name: "module_a",
+					Values: map[string]string{
+						"dev":     "1.1.0",
+						"prod-us": "1.0.0",
+					},
+					Status: domain.StatusOutOfSync,
+					DiffResults: []domain.DiffResult{{
+						Output: []byte(`diff --git a/README.md b/README.md
+--- a/README.md
++++ b/README.md
+@@ -1,3 +1,3 @@
+ Usage:
+-` + "```" + `hcl
++` + "````" + `hcl
+`),
+						BaseLabel: "prod-us",
+						HeadLabel: "dev",
+						BaseRef:   "1.0.0",
+						HeadRef:   "1.1.0",
+					}},
+				},
+				{
+					Name: "module_b",
+					Values: map[string]string{
+						"dev":     "a|b",
+						"prod-us": "<b>c</b>",
+						"prod-eu": "a|b",
+					},
+					Status: domain.StatusOutOfSync,
+				},
+			},
+		}
+
+		var buf bytes.Buffer
+
+		// WHEN
+		err := RenderMarkdown(&buf, []domain.ComparisonResult{result})
+
+		// THEN
+		require.NoError(t, err)
+		snaps.MatchSnapshot(t, buf.String())
+	})
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"slices"
 	"strings"
 
 	"github.com/alecthomas/chroma/v2/quick"
@@ -15,23 +16,82 @@ import (
 
 var errCouldntRenderStdout = errors.New("couldn't render stdout")
 
-func RenderStdout(writer io.Writer, result domain.ComparisonResult, plain bool) error {
-	rows := make([][]string, 0, len(result.Modules))
+var commitHashStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("11"))
 
-	rowStatuses := make(map[int]domain.ModuleStatus)
+func RenderStdout(writer io.Writer, results []domain.ComparisonResult, plain bool) error {
+	var output strings.Builder
 
-	for i, module := range result.Modules {
-		row := make([]string, 0, len(result.SourceLabels)+2)
-		row = append(row, module.Name)
-		rowStatuses[i] = module.Status
+	headingStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("11"))
 
-		for _, label := range result.SourceLabels {
-			value, ok := module.Values[label]
-			if ok {
-				row = append(row, value)
+	for i, result := range results {
+		if len(results) > 1 {
+			if i > 0 {
+				output.WriteString("\n")
+			}
+
+			if plain {
+				output.WriteString(result.Name)
 			} else {
-				row = append(row, "-")
+				output.WriteString(headingStyle.Render(result.Name))
 			}
+			output.WriteString("\n")
+		}
+
+		output.WriteString(renderStdoutResult(result, plain))
+	}
+
+	_, err := fmt.Fprint(writer, output.String())
+	if err != nil {
+		return fmt.Errorf("%w: %w", errCouldntRenderStdout, err)
+	}
+
+	return nil
+}
+
+func renderStdoutResult(result domain.ComparisonResult, plain bool) string {
+	columns := valueColumns(result)
+	rows := make([][]string, 0, len(result.Modules)+1)
+
+	headers := make([]string, 0, len(columns)+3)
+	headers = append(headers, result.Kind.Noun())
+	// when several attributes are compared, the headers name the attributes,
+	// and the first row holds the labels under each of them
+	grouped := len(result.AttributeKeys) > 0
+	if grouped {
+		labelRow := make([]string, 0, len(columns)+3)
+		labelRow = append(labelRow, "")
+		for _, column := range columns {
+			if column.label == result.SourceLabels[0] {
+				headers = append(headers, column.attributeKey)
+			} else {
+				headers = append(headers, "")
+			}
+			labelRow = append(labelRow, column.label)
+		}
+		rows = append(rows, labelRow)
+	} else {
+		for _, column := range columns {
+			headers = append(headers, column.label)
+		}
+	}
+	if result.SemverCfg != nil {
+		headers = append(headers, "drift")
+	}
+	headers = append(headers, "in-sync")
+
+	rowStatuses := make(map[int]domain.ModuleStatus)
+
+	for _, module := range result.Modules {
+		row := make([]string, 0, len(columns)+3)
+		row = append(row, module.Name)
+		rowStatuses[len(rows)] = module.Status
+
+		for _, column := range columns {
+			row = append(row, valueCell(module, column, "-"))
+		}
+
+		if result.SemverCfg != nil {
+			row = append(row, driftCell(module))
 		}
 
 		row = append(row, module.Status.Symbol())
@@ -41,11 +101,7 @@ func RenderStdout(writer io.Writer, result domain.ComparisonResult, plain bool)
 	plainStyle := lipgloss.NewStyle().PaddingRight(4)
 	outOfSyncStyle := plainStyle.Foreground(lipgloss.Color("9"))
 	notApplicableStyle := plainStyle.Foreground(lipgloss.Color("8"))
-
-	headers := make([]string, 0, len(result.SourceLabels)+2)
-	headers = append(headers, "module")
-	headers = append(headers, result.SourceLabels...)
-	headers = append(headers, "in-sync")
+	aheadOfUpstreamStyle := plainStyle.Foreground(lipgloss.Color("13"))
 
 	tbl := table.New().
 		Border(lipgloss.HiddenBorder()).
@@ -62,6 +118,8 @@ func RenderStdout(writer io.Writer, result domain.ComparisonResult, plain bool)
 			switch status {
 			case domain.StatusOutOfSync:
 				return outOfSyncStyle
+			case domain.StatusAheadOfUpstream:
+				return aheadOfUpstreamStyle
 			case domain.StatusNotApplicable:
 				return notApplicableStyle
 			default:
@@ -76,30 +134,98 @@ func RenderStdout(writer io.Writer, result domain.ComparisonResult, plain bool)
 	output.WriteString(tbl.String())
 	output.WriteString("\n")
 
-	for _, module := range result.Modules {
-		if module.DiffResult != nil {
-			var diff string
-			if plain {
-				diff = string(module.DiffResult.Output)
-			} else {
-				diff = highlightDiff(string(module.DiffResult.Output))
+	if unresolved := unresolvedValues(result); len(unresolved) > 0 {
+		output.WriteString("\nunresolved values:\n")
+		for _, u := range unresolved {
+			fmt.Fprintf(&output, "  %s (%s): %s\n", u.module, u.where(), u.reason)
+		}
+	}
+
+	if hasValueDiffs(result) {
+		output.WriteString("\nvalue differences:\n")
+		for _, module := range result.Modules {
+			for _, valueDiff := range module.ValueDiffs {
+				fmt.Fprintf(&output, "  %s %s: %s\n", module.Name, valueDiff.Path, strings.Join(valueDiffValues(result.SourceLabels, valueDiff), ", "))
 			}
+		}
+	}
 
+	for _, module := range result.Modules {
+		for _, diffResult := range module.DiffResults {
 			fmt.Fprintf(&output, `
 %s %s..%s (%s..%s)
+`,
+				module.Name,
+				diffResult.BaseLabel,
+				diffResult.HeadLabel,
+				diffResult.BaseRef,
+				diffResult.HeadRef,
+			)
+
+			if len(diffResult.Commits) > 0 {
+				output.WriteString("\ncommits:\n")
+				for _, commit := range diffResult.Commits {
+					hash := commit.Hash
+					if !plain {
+						hash = commitHashStyle.Render(hash)
+					}
+					fmt.Fprintf(&output, "  %s %s\n", hash, commit.Subject)
+				}
+			}
+
+			if len(diffResult.Output) > 0 {
+				var diff string
+				if plain {
+					diff = string(diffResult.Output)
+				} else {
+					diff = highlightDiff(string(diffResult.Output))
+				}
+
+				fmt.Fprintf(&output, "\n%s\n", diff)
+			}
+		}
+
+		for _, diffErr := range module.DiffErrors {
+			fmt.Fprintf(&output, `
+%s %s..%s (%s..%s): diff unavailable
 
 %s
 `,
 				module.Name,
-				module.DiffResult.BaseLabel,
-				module.DiffResult.HeadLabel,
-				module.DiffResult.BaseRef,
-				module.DiffResult.HeadRef,
-				diff,
+				diffErr.BaseLabel,
+				diffErr.HeadLabel,
+				diffErr.BaseRef,
+				diffErr.HeadRef,
+				diffErrorDetails(diffErr),
 			)
 		}
 	}
 
+	return output.String()
+}
+
+func RenderInputsStdout(writer io.Writer, results []domain.InputsResult, plain bool) error {
+	var output strings.Builder
+
+	headingStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("11"))
+
+	for i, result := range results {
+		if len(results) > 1 {
+			if i > 0 {
+				output.WriteString("\n")
+			}
+
+			if plain {
+				output.WriteString(result.Name)
+			} else {
+				output.WriteString(headingStyle.Render(result.Name))
+			}
+			output.WriteString("\n")
+		}
+
+		output.WriteString(renderInputsStdoutResult(result, plain))
+	}
+
 	_, err := fmt.Fprint(writer, output.String())
 	if err != nil {
 		return fmt.Errorf("%w: %w", errCouldntRenderStdout, err)
@@ -108,6 +234,66 @@ func RenderStdout(writer io.Writer, result domain.ComparisonResult, plain bool)
 	return nil
 }
 
+func renderInputsStdoutResult(result domain.InputsResult, plain bool) string {
+	if len(result.Modules) == 0 {
+		return "no modules are present in more than one source\n"
+	}
+
+	headers := make([]string, 0, len(result.SourceLabels)+3)
+	headers = append(headers, "module", "argument")
+	headers = append(headers, result.SourceLabels...)
+	headers = append(headers, "status")
+
+	var rows [][]string
+	rowStatuses := make(map[int]domain.ArgumentStatus)
+
+	for _, module := range result.Modules {
+		for i, argument := range module.Arguments {
+			row := make([]string, 0, len(headers))
+			// the module's name is only shown on its first row
+			if i == 0 {
+				row = append(row, module.Name)
+			} else {
+				row = append(row, "")
+			}
+			row = append(row, argument.Name)
+
+			for _, label := range result.SourceLabels {
+				row = append(row, argumentCell(module, argument, label))
+			}
+
+			row = append(row, argument.Status.String())
+			rowStatuses[len(rows)] = argument.Status
+			rows = append(rows, row)
+		}
+	}
+
+	plainStyle := lipgloss.NewStyle().PaddingRight(4)
+	differsStyle := plainStyle.Foreground(lipgloss.Color("9"))
+	missingStyle := plainStyle.Foreground(lipgloss.Color("11"))
+
+	tbl := table.New().
+		Border(lipgloss.HiddenBorder()).
+		StyleFunc(func(row, _ int) lipgloss.Style {
+			if plain {
+				return plainStyle
+			}
+
+			switch rowStatuses[row] {
+			case domain.ArgumentDiffers:
+				return differsStyle
+			case domain.ArgumentMissing:
+				return missingStyle
+			default:
+				return plainStyle
+			}
+		}).
+		Headers(headers...).
+		Rows(rows...)
+
+	return tbl.String() + "\n"
+}
+
 func highlightDiff(diff string) string {
 	var buf bytes.Buffer
 	err := quick.Highlight(&buf, diff, "diff", "terminal16", "native")
@@ -117,3 +303,165 @@ func highlightDiff(diff string) string {
 
 	return buf.String()
 }
+
+func diffErrorDetails(diffErr domain.DiffError) string {
+	var details strings.Builder
+	details.WriteString(diffErr.Message)
+	if diffErr.ExitCode >= 0 {
+		fmt.Fprintf(&details, " (exit code: %d)", diffErr.ExitCode)
+	}
+
+	if diffErr.Stderr != "" {
+		details.WriteString("\n\n")
+		details.WriteString(diffErr.Stderr)
+	}
+
+	return details.String()
+}
+
+// valueColumn is a column of a result's table that holds values; columns are
+// grouped by attribute when a comparison has more than one.
+type valueColumn struct {
+	// empty when a single attribute is compared
+	attributeKey string
+	label        string
+}
+
+func valueColumns(result domain.ComparisonResult) []valueColumn {
+	if len(result.AttributeKeys) == 0 {
+		columns := make([]valueColumn, 0, len(result.SourceLabels))
+		for _, label := range result.SourceLabels {
+			columns = append(columns, valueColumn{label: label})
+		}
+		return columns
+	}
+
+	columns := make([]valueColumn, 0, len(result.AttributeKeys)*len(result.SourceLabels))
+	for _, attributeKey := range result.AttributeKeys {
+		for _, label := range result.SourceLabels {
+			columns = append(columns, valueColumn{attributeKey: attributeKey, label: label})
+		}
+	}
+
+	return columns
+}
+
+// name returns the column's name when columns can't be visually grouped
+func (c valueColumn) name() string {
+	if c.attributeKey == "" {
+		return c.label
+	}
+
+	return fmt.Sprintf("%s (%s)", c.attributeKey, c.label)
+}
+
+// attributeValues returns a module's values for an attribute; the primary
+// attribute's values are returned for an empty key.
+func attributeValues(module domain.ModuleResult, attributeKey string) domain.AttributeValues {
+	if attributeKey == "" {
+		return domain.AttributeValues{Values: module.Values, Unresolved: module.Unresolved}
+	}
+
+	return module.Attributes[attributeKey]
+}
+
+// valueCell returns what's shown for a module's value in a column: the value
+// itself, "?" if it couldn't be resolved, or missing if it's absent.
+func valueCell(module domain.ModuleResult, column valueColumn, missing string) string {
+	values := attributeValues(module, column.attributeKey)
+	if value, ok := values.Values[column.label]; ok {
+		return value
+	}
+
+	if _, ok := values.Unresolved[column.label]; ok {
+		return "?"
+	}
+
+	return missing
+}
+
+type unresolvedValue struct {
+	module string
+	// empty when a single attribute is compared
+	attributeKey string
+	label        string
+	reason       string
+}
+
+// where returns the label of an unresolved value, along with its attribute if
+// there are several
+func (u unresolvedValue) where() string {
+	if u.attributeKey == "" {
+		return u.label
+	}
+
+	return fmt.Sprintf("%s, %s", u.attributeKey, u.label)
+}
+
+// unresolvedValues returns the values in a result that couldn't be resolved,
+// in the order of modules and columns.
+func unresolvedValues(result domain.ComparisonResult) []unresolvedValue {
+	var values []unresolvedValue
+	for _, module := range result.Modules {
+		for _, column := range valueColumns(result) {
+			if reason, ok := attributeValues(module, column.attributeKey).Unresolved[column.label]; ok {
+				values = append(values, unresolvedValue{
+					module:       module.Name,
+					attributeKey: column.attributeKey,
+					label:        column.label,
+					reason:       reason,
+				})
+			}
+		}
+	}
+
+	return values
+}
+
+func hasValueDiffs(result domain.ComparisonResult) bool {
+	for _, module := range result.Modules {
+		if len(module.ValueDiffs) > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// valueDiffValues returns a value diff's values in the order of labels, as
+// label=value; labels whose values don't have the diff's path get a "-".
+func valueDiffValues(labels []string, valueDiff domain.ValueDiff) []string {
+	values := make([]string, 0, len(labels))
+	for _, label := range labels {
+		value, ok := valueDiff.Values[label]
+		if !ok {
+			value = "-"
+		}
+		values = append(values, fmt.Sprintf("%s=%s", label, value))
+	}
+
+	return values
+}
+
+func driftCell(module domain.ModuleResult) string {
+	if module.Drift == domain.DriftNone {
+		return "-"
+	}
+
+	return module.Drift.String()
+}
+
+// argumentCell returns what's shown for an argument's value in a label's
+// column: the value itself, "-" if the argument isn't set in the label, or
+// nothing if the module isn't present in it.
+func argumentCell(module domain.ModuleInputs, argument domain.ModuleArgument, label string) string {
+	if value, ok := argument.Values[label]; ok {
+		return value
+	}
+
+	if slices.Contains(module.Labels, label) {
+		return "-"
+	}
+
+	return ""
+}
@@ -39,7 +39,7 @@ func TestRenderStdout(t *testing.T) {
 		var buf bytes.Buffer
 
 		// WHEN
-		err := RenderStdout(&buf, result, true)
+		err := RenderStdout(&buf, []domain.ComparisonResult{result}, true)
 
 		// THEN
 		require.NoError(t, err)
@@ -77,7 +77,7 @@ func TestRenderStdout(t *testing.T) {
 		var buf bytes.Buffer
 
 		// WHEN
-		err := RenderStdout(&buf, result, true)
+		err := RenderStdout(&buf, []domain.ComparisonResult{result}, true)
 
 		// THEN
 		require.NoError(t, err)
@@ -113,7 +113,7 @@ func TestRenderStdout(t *testing.T) {
 		var buf bytes.Buffer
 
 		// WHEN
-		err := RenderStdout(&buf, result, true)
+		err := RenderStdout(&buf, []domain.ComparisonResult{result}, true)
 
 		// THEN
 		require.NoError(t, err)
@@ -149,7 +149,7 @@ func TestRenderStdout(t *testing.T) {
 		var buf bytes.Buffer
 
 		// WHEN
-		err := RenderStdout(&buf, result, true)
+		err := RenderStdout(&buf, []domain.ComparisonResult{result}, true)
 
 		// THEN
 		require.NoError(t, err)
@@ -192,7 +192,7 @@ func TestRenderStdout(t *testing.T) {
 		var buf bytes.Buffer
 
 		// WHEN
-		err := RenderStdout(&buf, result, true)
+		err := RenderStdout(&buf, []domain.ComparisonResult{result}, true)
 
 		// THEN
 		require.NoError(t, err)
@@ -200,4 +200,539 @@ func TestRenderStdout(t *testing.T) {
 		output := buf.String()
 		snaps.MatchSnapshot(t, output)
 	})
+	t.Run("works for multiple comparisons", func(t *testing.T) {
+		// GIVEN
+		results := []domain.ComparisonResult{
+			{
+				Name:         "apps",
+				SourceLabels: []string{"dev", "prod"},
+				Modules: []domain.ModuleResult{
+					{
+						Name: "module_a",
+						Values: map[string]string{
+							"dev":  "1.0.0",
+							"prod": "1.0.0",
+						},
+						Status: domain.StatusInSync,
+					},
+				},
+			},
+			{
+				Name:         "data",
+				SourceLabels: []string{"dev", "prod"},
+				Modules: []domain.ModuleResult{
+					{
+						Name: "module_b",
+						Values: map[string]string{
+							"dev":  "2.1.0",
+							"prod": "2.0.0",
+						},
+						Status: domain.StatusOutOfSync,
+					},
+				},
+			},
+		}
+
+		var buf bytes.Buffer
+
+		// WHEN
+		err := RenderStdout(&buf, results, true)
+
+		// THEN
+		require.NoError(t, err)
+
+		output := buf.String()
+		snaps.MatchSnapshot(t, output)
+	})
+	t.Run("works when semver drift is classified", func(t *testing.T) {
+		// GIVEN
+		result := domain.ComparisonResult{
+			SourceLabels: []string{"dev", "prod-us", "prod-eu"},
+			Modules: []domain.ModuleResult{
+				{
+					Name: "module_a",
+					Values: map[string]string{
+						"dev":     "1.0.0",
+						"prod-us": "1.0.0",
+						"prod-eu": "1.0.0",
+					},
+					Status: domain.StatusInSync,
+				},
+				{
+					Name: "module_b",
+					Values: map[string]string{
+						"dev":     "2.1.0",
+						"prod-us": "2.0.0",
+						"prod-eu": "2.0.0",
+					},
+					Status: domain.StatusOutOfSync,
+					Drift:  domain.DriftMinor,
+				},
+				{
+					Name: "module_c",
+					Values: map[string]string{
+						"dev":     "main",
+						"prod-us": "3.0.0",
+						"prod-eu": "3.0.0",
+					},
+					Status: domain.StatusOutOfSync,
+					Drift:  domain.DriftUnknown,
+				},
+			},
+			SemverCfg: &domain.SemverConfig{FailOn: domain.DriftMajor},
+		}
+
+		var buf bytes.Buffer
+
+		// WHEN
+		err := RenderStdout(&buf, []domain.ComparisonResult{result}, true)
+
+		// THEN
+		require.NoError(t, err)
+
+		output := buf.String()
+		snaps.MatchSnapshot(t, output)
+	})
+
+	t.Run("works when modules are ahead of upstream", func(t *testing.T) {
+		// GIVEN
+		result := domain.ComparisonResult{
+			Name:         "apps",
+			SourceLabels: []string{"dev", "staging", "prod"},
+			Modules: []domain.ModuleResult{
+				{
+					Name: "module_a",
+					Values: map[string]string{
+						"dev":     "1.1.0",
+						"staging": "1.0.0",
+						"prod":    "1.0.0",
+					},
+					Status: domain.StatusOutOfSync,
+				},
+				{
+					Name: "module_b",
+					Values: map[string]string{
+						"dev":     "1.0.0",
+						"staging": "1.0.0",
+						"prod":    "1.2.0",
+					},
+					Status: domain.StatusAheadOfUpstream,
+				},
+			},
+		}
+
+		var buf bytes.Buffer
+
+		// WHEN
+		err := RenderStdout(&buf, []domain.ComparisonResult{result}, true)
+
+		// THEN
+		require.NoError(t, err)
+		snaps.MatchSnapshot(t, buf.String())
+	})
+
+	t.Run("works when values are unresolved", func(t *testing.T) {
+		// GIVEN
+		result := domain.ComparisonResult{
+			Name:         "apps",
+			SourceLabels: []string{"dev", "prod"},
+			Modules: []domain.ModuleResult{
+				{
+					Name: "module_a",
+					Values: map[string]string{
+						"prod": "1.0.0",
+					},
+					Status: domain.StatusOutOfSync,
+					Unresolved: map[string]string{
+						"dev": `couldn't resolve value: variable "module_a_version" has no default, and isn't set in any var file`,
+					},
+				},
+				{
+					Name: "module_b",
+					Values: map[string]string{
+						"dev":  "2.0.0",
+						"prod": "2.0.0",
+					},
+					Status: domain.StatusInSync,
+				},
+			},
+		}
+
+		var buf bytes.Buffer
+
+		// WHEN
+		err := RenderStdout(&buf, []domain.ComparisonResult{result}, true)
+
+		// THEN
+		require.NoError(t, err)
+		snaps.MatchSnapshot(t, buf.String())
+	})
+
+	t.Run("works when multiple attributes are compared", func(t *testing.T) {
+		// GIVEN
+		result := domain.ComparisonResult{
+			Name:          "apps",
+			SourceLabels:  []string{"dev", "prod"},
+			AttributeKeys: []string{"version", "source"},
+			Modules: []domain.ModuleResult{
+				{
+					Name: "module_a",
+					Values: map[string]string{
+						"dev":  "5.1.0",
+						"prod": "5.1.0",
+					},
+					Status: domain.StatusOutOfSync,
+					Attributes: map[string]domain.AttributeValues{
+						"version": {
+							Values: map[string]string{
+								"dev":  "5.1.0",
+								"prod": "5.1.0",
+							},
+						},
+						"source": {
+							Values: map[string]string{
+								"dev":  "terraform-aws-modules/vpc/aws",
+								"prod": "example-org/vpc/aws",
+							},
+						},
+					},
+				},
+				{
+					Name: "module_b",
+					Values: map[string]string{
+						"prod": "20.0.0",
+					},
+					Status: domain.StatusOutOfSync,
+					Unresolved: map[string]string{
+						"dev": `couldn't resolve value: local "eks_version" is not declared`,
+					},
+					Attributes: map[string]domain.AttributeValues{
+						"version": {
+							Values: map[string]string{
+								"prod": "20.0.0",
+							},
+							Unresolved: map[string]string{
+								"dev": `couldn't resolve value: local "eks_version" is not declared`,
+							},
+						},
+						"source": {
+							Values: map[string]string{
+								"dev":  "terraform-aws-modules/eks/aws",
+								"prod": "terraform-aws-modules/eks/aws",
+							},
+						},
+					},
+				},
+				{
+					Name:   "module_c",
+					Values: map[string]string{},
+					Status: domain.StatusInSync,
+					Attributes: map[string]domain.AttributeValues{
+						"version": {
+							Values: map[string]string{},
+						},
+						"source": {
+							Values: map[string]string{
+								"dev":  "git::https://github.com/example/modules.git//iam?ref=v1.0.0",
+								"prod": "git::https://github.com/example/modules.git//iam?ref=v1.0.0",
+							},
+						},
+					},
+				},
+			},
+		}
+
+		var buf bytes.Buffer
+
+		// WHEN
+		err := RenderStdout(&buf, []domain.ComparisonResult{result}, true)
+
+		// THEN
+		require.NoError(t, err)
+		snaps.MatchSnapshot(t, buf.String())
+	})
+
+	t.Run("works when complex values differ", func(t *testing.T) {
+		// GIVEN
+		result := domain.ComparisonResult{
+			Name:         "apps",
+			SourceLabels: []string{"dev", "prod"},
+			Modules: []domain.ModuleResult{
+				{
+					Name: "module_a",
+					Values: map[string]string{
+						"dev":  `[{"name":"default","size":3},{"name":"spot","size":2}]`,
+						"prod": `[{"name":"default","size":5}]`,
+					},
+					Status: domain.StatusOutOfSync,
+					ValueDiffs: []domain.ValueDiff{
+						{
+							Path: "node_pools[0].size",
+							Values: map[string]string{
+								"dev":  "3",
+								"prod": "5",
+							},
+						},
+						{
+							Path: "node_pools[1].name",
+							Values: map[string]string{
+								"dev": `"spot"`,
+							},
+						},
+					},
+				},
+				{
+					Name: "module_b",
+					Values: map[string]string{
+						"dev":  `[{"name":"default","size":1}]`,
+						"prod": `[{"name":"default","size":1}]`,
+					},
+					Status: domain.StatusInSync,
+				},
+			},
+		}
+
+		var buf bytes.Buffer
+
+		// WHEN
+		err := RenderStdout(&buf, []domain.ComparisonResult{result}, true)
+
+		// THEN
+		require.NoError(t, err)
+		snaps.MatchSnapshot(t, buf.String())
+	})
+
+	t.Run("works when diffs are unavailable", func(t *testing.T) {
+		// GIVEN
+		result := domain.ComparisonResult{
+			Name:         "apps",
+			SourceLabels: []string{"dev", "prod"},
+			Modules: []domain.ModuleResult{
+				{
+					Name: "module_a",
+					Values: map[string]string{
+						"dev":  "1.1.0",
+						"prod": "1.0.0",
+					},
+					Status: domain.StatusOutOfSync,
+					DiffErrors: []domain.DiffError{{
+						BaseLabel: "prod",
+						HeadLabel: "dev",
+						BaseRef:   "1.0.0",
+						HeadRef:   "1.1.0",
+						ExitCode:  128,
+						Message:   "command exited with non success exit code",
+						Stderr:    "fatal: ambiguous argument 'module-a-v1.0.0..module-a-v1.1.0': unknown revision",
+					}},
+				},
+				{
+					Name: "module_b",
+					Values: map[string]string{
+						"dev":  "2.1.0",
+						"prod": "2.0.0",
+					},
+					Status: domain.StatusOutOfSync,
+					DiffErrors: []domain.DiffError{{
+						BaseLabel: "prod",
+						HeadLabel: "dev",
+						BaseRef:   "2.0.0",
+						HeadRef:   "2.1.0",
+						ExitCode:  -1,
+						Message:   "diff command timed out after 30s",
+					}},
+				},
+			},
+		}
+
+		var buf bytes.Buffer
+
+		// WHEN
+		err := RenderStdout(&buf, []domain.ComparisonResult{result}, true)
+
+		// THEN
+		require.NoError(t, err)
+		snaps.MatchSnapshot(t, buf.String())
+	})
+
+	t.Run("works when commit logs are present", func(t *testing.T) {
+		// GIVEN
+		result := domain.ComparisonResult{
+			Name:         "apps",
+			SourceLabels: []string{"dev", "prod"},
+			Modules: []domain.ModuleResult{
+				{
+					Name: "module_a",
+					Values: map[string]string{
+						"dev":  "1.1.0",
+						"prod": "1.0.0",
+					},
+					Status: domain.StatusOutOfSync,
+					DiffResults: []domain.DiffResult{{
+						Output: []byte(`diff --git a/main.tf b/main.tf
+--- a/main.tf
++++ b/main.tf
+@@ -1,2 +1,2 @@
+-  count = 1
++  count = 2
+`),
+						BaseLabel: "prod",
+						HeadLabel: "dev",
+						BaseRef:   "1.0.0",
+						HeadRef:   "1.1.0",
+						Commits: []domain.Commit{
+							{Hash: "a1b2c3d", Subject: "module_a: bump count"},
+							{Hash: "e4f5a6b", Subject: "module_a: fix <tags> in descriptions"},
+						},
+					}},
+				},
+				{
+					Name: "module_b",
+					Values: map[string]string{
+						"dev":  "2.1.0",
+						"prod": "2.0.0",
+					},
+					Status: domain.StatusOutOfSync,
+					DiffResults: []domain.DiffResult{{
+						BaseLabel: "prod",
+						HeadLabel: "dev",
+						BaseRef:   "2.0.0",
+						HeadRef:   "2.1.0",
+						Commits: []domain.Commit{
+							{Hash: "0f1e2d3", Subject: "module_b: update docs"},
+						},
+					}},
+				},
+			},
+		}
+
+		var buf bytes.Buffer
+
+		// WHEN
+		err := RenderStdout(&buf, []domain.ComparisonResult{result}, true)
+
+		// THEN
+		require.NoError(t, err)
+		snaps.MatchSnapshot(t, buf.String())
+	})
+
+	t.Run("works for providers", func(t *testing.T) {
+		// GIVEN
+		result := domain.ComparisonResult{
+			Name:         "providers",
+			Kind:         domain.ProvidersResult,
+			SourceLabels: []string{"dev", "prod"},
+			Modules: []domain.ModuleResult{
+				{
+					Name: "aws",
+					Values: map[string]string{
+						"dev":  "~> 5.40",
+						"prod": "~> 5.31",
+					},
+					Status: domain.StatusOutOfSync,
+				},
+				{
+					Name: "terraform",
+					Values: map[string]string{
+						"dev":  ">= 1.6.0",
+						"prod": ">= 1.6.0",
+					},
+					Status: domain.StatusInSync,
+				},
+			},
+		}
+
+		var buf bytes.Buffer
+
+		// WHEN
+		err := RenderStdout(&buf, []domain.ComparisonResult{result}, true)
+
+		// THEN
+		require.NoError(t, err)
+		snaps.MatchSnapshot(t, buf.String())
+	})
+}
+
+func TestRenderInputsStdout(t *testing.T) {
+	t.Run("works for a single comparison", func(t *testing.T) {
+		// GIVEN
+		result := getInputsResult()
+		var buf bytes.Buffer
+
+		// WHEN
+		err := RenderInputsStdout(&buf, []domain.InputsResult{result}, true)
+
+		// THEN
+		require.NoError(t, err)
+		snaps.MatchSnapshot(t, buf.String())
+	})
+
+	t.Run("works for multiple comparisons", func(t *testing.T) {
+		// GIVEN
+		result := getInputsResult()
+		empty := domain.InputsResult{
+			Name:         "data",
+			SourceLabels: []string{"dev", "prod"},
+		}
+		var buf bytes.Buffer
+
+		// WHEN
+		err := RenderInputsStdout(&buf, []domain.InputsResult{result, empty}, true)
+
+		// THEN
+		require.NoError(t, err)
+		snaps.MatchSnapshot(t, buf.String())
+	})
+}
+
+func getInputsResult() domain.InputsResult {
+	return domain.InputsResult{
+		Name:         "apps",
+		SourceLabels: []string{"dev", "qa", "prod"},
+		Modules: []domain.ModuleInputs{
+			{
+				Name:   "module_a",
+				Labels: []string{"dev", "prod"},
+				Arguments: []domain.ModuleArgument{
+					{
+						Name: "count",
+						Values: map[string]string{
+							"dev":  "1",
+							"prod": "3",
+						},
+						Status: domain.ArgumentDiffers,
+					},
+					{
+						Name: "tags",
+						Values: map[string]string{
+							"dev":  "{\n  team = \"platform\"\n  env  = \"dev\"\n}",
+							"prod": "{\n  team = \"platform\"\n  env  = \"prod\"\n}",
+						},
+						Status: domain.ArgumentDiffers,
+					},
+				},
+			},
+			{
+				Name:   "module_b",
+				Labels: []string{"dev", "qa", "prod"},
+				Arguments: []domain.ModuleArgument{
+					{
+						Name: "backup_retention_days",
+						Values: map[string]string{
+							"prod": "7",
+						},
+						Status: domain.ArgumentMissing,
+					},
+					{
+						Name: "instance_class",
+						Values: map[string]string{
+							"dev":  "var.instance_class",
+							"qa":   "var.instance_class",
+							"prod": "var.instance_class",
+						},
+						Status: domain.ArgumentSame,
+					},
+				},
+			},
+		},
+	}
 }
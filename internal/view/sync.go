@@ -0,0 +1,74 @@
+package view
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/dhth/tflens/internal/domain"
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+var errCouldntRenderSyncPlan = errors.New("couldn't render sync plan")
+
+// RenderSyncPlan writes a summary of the changes in a sync plan. When dryRun is
+// true, a unified diff of the edits that would be made is written as well.
+func RenderSyncPlan(writer io.Writer, plan domain.SyncPlan, dryRun bool) error {
+	if len(plan.Changes) == 0 {
+		_, err := fmt.Fprintf(writer, "nothing to sync; modules in %q already match the ones in %q\n", plan.ToLabel, plan.FromLabel)
+		if err != nil {
+			return fmt.Errorf("%w: %w", errCouldntRenderSyncPlan, err)
+		}
+
+		return nil
+	}
+
+	if dryRun {
+		for _, file := range plan.Files {
+			diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+				A:        diffLines(file.Before),
+				B:        diffLines(file.After),
+				FromFile: "a/" + file.Path,
+				ToFile:   "b/" + file.Path,
+				Context:  3,
+			})
+			if err != nil {
+				return fmt.Errorf("%w: %w", errCouldntRenderSyncPlan, err)
+			}
+
+			_, err = fmt.Fprint(writer, diff)
+			if err != nil {
+				return fmt.Errorf("%w: %w", errCouldntRenderSyncPlan, err)
+			}
+		}
+
+		_, err := fmt.Fprintln(writer)
+		if err != nil {
+			return fmt.Errorf("%w: %w", errCouldntRenderSyncPlan, err)
+		}
+	}
+
+	action := "updated"
+	if dryRun {
+		action = "would update"
+	}
+
+	for _, change := range plan.Changes {
+		_, err := fmt.Fprintf(writer, "%s %s in %s: %s -> %s\n", action, change.Module, change.File, change.OldValue, change.NewValue)
+		if err != nil {
+			return fmt.Errorf("%w: %w", errCouldntRenderSyncPlan, err)
+		}
+	}
+
+	return nil
+}
+
+func diffLines(content []byte) []string {
+	lines := strings.SplitAfter(string(content), "\n")
+	if len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	return lines
+}
@@ -0,0 +1,76 @@
+package view
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/dhth/tflens/internal/domain"
+	"github.com/gkampitakis/go-snaps/snaps"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderSyncPlan(t *testing.T) {
+	plan := domain.SyncPlan{
+		FromLabel: "dev",
+		ToLabel:   "prod",
+		Changes: []domain.SyncChange{
+			{
+				Module:   "module_a",
+				File:     "environments/prod/main.tf",
+				OldValue: "1.0.0",
+				NewValue: "1.1.0",
+			},
+		},
+		Files: []domain.FileChange{
+			{
+				Path: "environments/prod/main.tf",
+				Before: []byte(`module "module_a" {
+  # pinned
+  source = "git@github.com:owner/repo//modules/a?ref=v1.0.0"
+}
+`),
+				After: []byte(`module "module_a" {
+  # pinned
+  source = "git@github.com:owner/repo//modules/a?ref=v1.1.0"
+}
+`),
+			},
+		},
+	}
+
+	t.Run("works for dry runs", func(t *testing.T) {
+		// GIVEN
+		var buf bytes.Buffer
+
+		// WHEN
+		err := RenderSyncPlan(&buf, plan, true)
+
+		// THEN
+		require.NoError(t, err)
+		snaps.MatchSnapshot(t, buf.String())
+	})
+
+	t.Run("works for applied changes", func(t *testing.T) {
+		// GIVEN
+		var buf bytes.Buffer
+
+		// WHEN
+		err := RenderSyncPlan(&buf, plan, false)
+
+		// THEN
+		require.NoError(t, err)
+		snaps.MatchSnapshot(t, buf.String())
+	})
+
+	t.Run("works when there's nothing to sync", func(t *testing.T) {
+		// GIVEN
+		var buf bytes.Buffer
+
+		// WHEN
+		err := RenderSyncPlan(&buf, domain.SyncPlan{FromLabel: "dev", ToLabel: "prod"}, false)
+
+		// THEN
+		require.NoError(t, err)
+		snaps.MatchSnapshot(t, buf.String())
+	})
+}
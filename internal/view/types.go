@@ -11,13 +11,48 @@ type HTMLConfig struct {
 }
 
 type HTMLData struct {
-	Title     string
-	Columns   []string
-	Rows      []HTMLRow
-	Diffs     []HTMLDiff
+	Title string
+	// Columns and Rows are only populated when a single comparison is rendered;
+	// Sections should be used for rendering multiple comparisons
+	Columns []string
+	// ColumnGroups span Columns, and name the attribute each group of values
+	// belongs to; they're only populated when more than one attribute is
+	// compared
+	ColumnGroups []HTMLColumnGroup
+	Rows         []HTMLRow
+	// Diffs contains the diffs for all comparisons
+	Diffs []HTMLDiff
+	// Commits contains the commit logs for all comparisons
+	Commits []HTMLCommitLog
+	// DiffCSS styles the highlighted output of diffs (to be used on an element
+	// with the "chroma" class); it's only populated when diffs are present
+	DiffCSS   template.CSS
+	Sections  []HTMLSection
 	Timestamp string
 }
 
+type HTMLSection struct {
+	Name         string
+	Columns      []string
+	ColumnGroups []HTMLColumnGroup
+	Rows         []HTMLRow
+	Diffs        []HTMLDiff
+	Commits      []HTMLCommitLog
+	// values that couldn't be resolved; these show up as "?" in Rows
+	Unresolved []HTMLUnresolvedValue
+	// parts of complex values that differ between labels
+	ValueDiffs []HTMLValueDiff
+	// set when values can span multiple lines; their text is then left
+	// aligned, and their line breaks are kept
+	AlignLeft bool
+}
+
+type HTMLColumnGroup struct {
+	// empty for columns that don't hold values
+	Name string
+	Span int
+}
+
 type HTMLRow struct {
 	Data   []string
 	Status string
@@ -25,11 +60,51 @@ type HTMLRow struct {
 
 type HTMLDiff struct {
 	ModuleName string
-	Output     template.HTML
+	// diff output, escaped and syntax highlighted (unless the diff command
+	// was configured to print HTML)
+	Output    template.HTML
+	BaseLabel string
+	HeadLabel string
+	BaseRef   string
+	HeadRef   string
+	// set when the diff couldn't be computed; Error then explains why
+	Unavailable bool
+	Error       string
+}
+
+type HTMLUnresolvedValue struct {
+	ModuleName string
+	// only set when more than one attribute is compared
+	AttributeKey string
+	Label        string
+	Reason       string
+}
+
+type HTMLValueDiff struct {
+	ModuleName string
+	Path       string
+	// one per label, in order
+	Values []HTMLLabelValue
+}
+
+type HTMLLabelValue struct {
+	Label string
+	// empty if the label's value doesn't have the path
+	Value string
+}
+
+type HTMLCommitLog struct {
+	ModuleName string
 	BaseLabel  string
 	HeadLabel  string
 	BaseRef    string
 	HeadRef    string
+	Commits    []HTMLCommit
+}
+
+type HTMLCommit struct {
+	Hash    string
+	Subject string
 }
 
 func NewHTMLData(title string, referenceTime time.Time) HTMLData {
@@ -1,9 +1,12 @@
 package main
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/dhth/tflens/internal/cmd"
 	"github.com/dhth/tflens/internal/domain"
@@ -12,10 +15,14 @@ import (
 var version = "dev"
 
 func main() {
-	err := cmd.Execute(version)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	err := cmd.Execute(ctx, version)
+	stop()
 	if err != nil {
 		switch {
 		case errors.Is(err, cmd.ErrModulesNotInSync):
+		case errors.Is(err, cmd.ErrModulesAheadOfUpstream):
+		case errors.Is(err, cmd.ErrDiffsUnavailable):
 		case errors.Is(err, cmd.ErrConfigValidationFoundErrors):
 		case errors.Is(err, domain.ErrCouldntParseConfig):
 			fmt.Fprintf(os.Stderr, "Error: %s", err.Error())
@@ -30,6 +37,34 @@ Let @dhth know about this via https://github.com/dhth/tflens/issues.
 `)
 		}
 
-		os.Exit(1)
+		os.Exit(exitCode(err))
 	}
 }
+
+// exitCode maps an error to the process' exit code. Drift and unavailable diffs
+// are reported as bits, so that a caller can tell them apart when both occur:
+// 1 for modules not in sync, 2 for modules ahead of upstream, and 4 for
+// unavailable diffs.
+func exitCode(err error) int {
+	if errors.Is(err, context.Canceled) {
+		return 130
+	}
+
+	code := 0
+	switch {
+	case errors.Is(err, cmd.ErrModulesAheadOfUpstream):
+		code |= 2
+	case errors.Is(err, cmd.ErrModulesNotInSync):
+		code |= 1
+	}
+
+	if errors.Is(err, cmd.ErrDiffsUnavailable) {
+		code |= 4
+	}
+
+	if code == 0 {
+		return 1
+	}
+
+	return code
+}
@@ -0,0 +1,142 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/gkampitakis/go-snaps/snaps"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompareInputsCmd(t *testing.T) {
+	fx, err := newFixture()
+	require.NoErrorf(t, err, "error setting up fixture: %s", err)
+
+	defer func() {
+		err := fx.cleanup()
+		require.NoErrorf(t, err, "error cleaning up fixture: %s", err)
+	}()
+
+	//-------------//
+	//  SUCCESSES  //
+	//-------------//
+
+	t.Run("help flag works", func(t *testing.T) {
+		// GIVEN
+		args := []string{
+			"compare-inputs",
+			"--help",
+		}
+
+		// WHEN
+		result, err := fx.runCmd(args)
+
+		// THEN
+		require.NoError(t, err)
+		snaps.MatchStandaloneSnapshot(t, result)
+	})
+
+	t.Run("works for correct config", func(t *testing.T) {
+		// GIVEN
+		args := []string{
+			"compare-inputs",
+			"--config-path", "testdata/config/good.yml",
+			"--stdout-plain",
+			"apps",
+		}
+
+		// WHEN
+		result, err := fx.runCmd(args)
+
+		// THEN
+		require.NoError(t, err)
+		snaps.MatchStandaloneSnapshot(t, result)
+	})
+
+	t.Run("works with json output format", func(t *testing.T) {
+		// GIVEN
+		args := []string{
+			"compare-inputs",
+			"--config-path", "testdata/config/good.yml",
+			"--output-format", "json",
+			"core",
+		}
+
+		// WHEN
+		result, err := fx.runCmd(args)
+
+		// THEN
+		require.NoError(t, err)
+		snaps.MatchStandaloneSnapshot(t, result)
+	})
+
+	t.Run("skips comparisons with terragrunt sources when running all", func(t *testing.T) {
+		// GIVEN
+		args := []string{
+			"compare-inputs",
+			"--config-path", "testdata/config/terragrunt.yml",
+			"--stdout-plain",
+			"--all",
+		}
+
+		// WHEN
+		result, err := fx.runCmd(args)
+
+		// THEN
+		require.NoError(t, err)
+		snaps.MatchStandaloneSnapshot(t, result)
+	})
+
+	//------------//
+	//  FAILURES  //
+	//------------//
+
+	t.Run("fails for an unsupported output format", func(t *testing.T) {
+		// GIVEN
+		args := []string{
+			"compare-inputs",
+			"--config-path", "testdata/config/good.yml",
+			"--output-format", "markdown",
+			"apps",
+		}
+
+		// WHEN
+		result, err := fx.runCmd(args)
+
+		// THEN
+		require.NoError(t, err)
+		snaps.MatchStandaloneSnapshot(t, result)
+	})
+
+	t.Run("fails for unknown comparison", func(t *testing.T) {
+		// GIVEN
+		args := []string{
+			"compare-inputs",
+			"--config-path", "testdata/config/good.yml",
+			"unknown",
+		}
+
+		// WHEN
+		result, err := fx.runCmd(args)
+
+		// THEN
+		require.NoError(t, err)
+		snaps.MatchStandaloneSnapshot(t, result)
+	})
+	t.Run("fails for a comparison with terragrunt sources", func(t *testing.T) {
+		// GIVEN
+		args := []string{
+			"compare-inputs",
+			"--config-path", "testdata/config/terragrunt.yml",
+			"--stdout-plain",
+			"terraform",
+			"terragrunt",
+		}
+
+		// WHEN
+		result, err := fx.runCmd(args)
+
+		// THEN
+		require.NoError(t, err)
+		snaps.MatchStandaloneSnapshot(t, result)
+	})
+}
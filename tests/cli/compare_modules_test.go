@@ -1,9 +1,13 @@
 package cli
 
 import (
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/gkampitakis/go-snaps/snaps"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -70,6 +74,280 @@ func TestCompareModulesCmd(t *testing.T) {
 		snaps.MatchStandaloneSnapshot(t, result)
 	})
 
+	t.Run("semver drift below the configured severity doesn't fail", func(t *testing.T) {
+		// GIVEN
+		args := []string{
+			"compare-modules",
+			"--config-path", "testdata/config/good.yml",
+			"--stdout-plain",
+			"--ignore-missing-modules",
+			"apps-semver",
+		}
+
+		// WHEN
+		result, err := fx.runCmd(args)
+
+		// THEN
+		require.NoError(t, err)
+		snaps.MatchStandaloneSnapshot(t, result)
+	})
+
+	t.Run("modules ahead of upstream fail with a distinct exit code", func(t *testing.T) {
+		// GIVEN
+		args := []string{
+			"compare-modules",
+			"--config-path", "testdata/config/good.yml",
+			"--stdout-plain",
+			"apps-promotion",
+		}
+
+		// WHEN
+		result, err := fx.runCmd(args)
+
+		// THEN
+		require.NoError(t, err)
+		snaps.MatchStandaloneSnapshot(t, result)
+	})
+
+	t.Run("modules ahead of upstream fail with a distinct exit code with html output format", func(t *testing.T) {
+		// GIVEN
+		htmlOutputPath := filepath.Join(fx.tempDir, "reports", "promotion.html")
+		args := []string{
+			"compare-modules",
+			"--config-path", "testdata/config/good.yml",
+			"--output-format", "html",
+			"--html-output", htmlOutputPath,
+			"apps-promotion",
+		}
+
+		// WHEN
+		result, err := fx.runCmd(args)
+
+		// THEN
+		require.NoError(t, err)
+		assert.Contains(t, result, "exit_code: 2\n")
+		assert.FileExists(t, htmlOutputPath)
+	})
+
+	t.Run("diff failures are reported per module when continuing on diff errors", func(t *testing.T) {
+		// GIVEN
+		args := []string{
+			"compare-modules",
+			"--config-path", "testdata/config/good.yml",
+			"--stdout-plain",
+			"--include-diffs",
+			"--continue-on-diff-errors",
+			"apps-diffs",
+		}
+
+		// WHEN
+		result, err := fx.runCmd(args)
+
+		// THEN
+		require.NoError(t, err)
+		snaps.MatchStandaloneSnapshot(t, result)
+	})
+
+	t.Run("diff failures lead to a non-zero exit code with html output format", func(t *testing.T) {
+		// GIVEN
+		htmlOutputPath := filepath.Join(fx.tempDir, "reports", "diff-failures.html")
+		args := []string{
+			"compare-modules",
+			"--config-path", "testdata/config/good.yml",
+			"--include-diffs",
+			"--continue-on-diff-errors",
+			"--output-format", "html",
+			"--html-output", htmlOutputPath,
+			"apps-diffs",
+		}
+
+		// WHEN
+		result, err := fx.runCmd(args)
+
+		// THEN
+		require.NoError(t, err)
+		assert.Contains(t, result, "exit_code: 5\n")
+		assert.FileExists(t, htmlOutputPath)
+	})
+
+	t.Run("works with multiple diff pairs", func(t *testing.T) {
+		// GIVEN
+		args := []string{
+			"compare-modules",
+			"--config-path", "testdata/config/good.yml",
+			"--stdout-plain",
+			"--include-diffs",
+			"apps-diff-pairs",
+		}
+
+		// WHEN
+		result, err := fx.runCmd(args)
+
+		// THEN
+		require.NoError(t, err)
+		snaps.MatchStandaloneSnapshot(t, result)
+	})
+
+	t.Run("reuses cached diffs", func(t *testing.T) {
+		// GIVEN
+		args := []string{
+			"compare-modules",
+			"--config-path", "testdata/config/good.yml",
+			"--stdout-plain",
+			"--include-diffs",
+			"--cache-dir", filepath.Join(fx.tempDir, "diff-cache"),
+			"apps-diff-pairs",
+		}
+		firstResult, err := fx.runCmd(args)
+		require.NoError(t, err)
+
+		// WHEN
+		result, err := fx.runCmd(args)
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, firstResult, result)
+		snaps.MatchStandaloneSnapshot(t, result)
+	})
+
+	t.Run("computes diffs without caching when the cache can't be set up", func(t *testing.T) {
+		// GIVEN
+		notADir := filepath.Join(fx.tempDir, "not-a-dir")
+		require.NoError(t, os.WriteFile(notADir, nil, 0o644))
+		args := []string{
+			"compare-modules",
+			"--config-path", "testdata/config/good.yml",
+			"--stdout-plain",
+			"--include-diffs",
+			"--cache-dir", filepath.Join(notADir, "diff-cache"),
+			"apps-diff-pairs",
+		}
+		uncachedArgs := []string{
+			"compare-modules",
+			"--config-path", "testdata/config/good.yml",
+			"--stdout-plain",
+			"--include-diffs",
+			"--no-cache",
+			"apps-diff-pairs",
+		}
+		uncachedResult, err := fx.runCmd(uncachedArgs)
+		require.NoError(t, err)
+
+		// WHEN
+		result, err := fx.runCmd(args)
+
+		// THEN
+		require.NoError(t, err)
+		assert.Contains(t, result, "warning: couldn't create cache directory")
+		stdout, _, _ := strings.Cut(result, "----- stderr -----")
+		uncachedStdout, _, _ := strings.Cut(uncachedResult, "----- stderr -----")
+		assert.Equal(t, uncachedStdout, stdout)
+	})
+
+	t.Run("works for multiple comparisons", func(t *testing.T) {
+		// GIVEN
+		args := []string{
+			"compare-modules",
+			"--config-path", "testdata/config/good.yml",
+			"--stdout-plain",
+			"apps",
+			"core",
+		}
+
+		// WHEN
+		result, err := fx.runCmd(args)
+
+		// THEN
+		require.NoError(t, err)
+		snaps.MatchStandaloneSnapshot(t, result)
+	})
+
+	t.Run("works with multiple jobs", func(t *testing.T) {
+		// GIVEN
+		args := []string{
+			"compare-modules",
+			"--config-path", "testdata/config/good.yml",
+			"--stdout-plain",
+			"--jobs", "3",
+			"apps",
+		}
+
+		// WHEN
+		result, err := fx.runCmd(args)
+
+		// THEN
+		require.NoError(t, err)
+		snaps.MatchStandaloneSnapshot(t, result)
+	})
+
+	t.Run("works for all comparisons", func(t *testing.T) {
+		// GIVEN
+		args := []string{
+			"compare-modules",
+			"--config-path", "testdata/config/good.yml",
+			"--output-format", "json",
+			"--all",
+		}
+
+		// WHEN
+		result, err := fx.runCmd(args)
+
+		// THEN
+		require.NoError(t, err)
+		snaps.MatchStandaloneSnapshot(t, result)
+	})
+
+	t.Run("works with json output format", func(t *testing.T) {
+		// GIVEN
+		args := []string{
+			"compare-modules",
+			"--config-path", "testdata/config/good.yml",
+			"--output-format", "json",
+			"apps",
+		}
+
+		// WHEN
+		result, err := fx.runCmd(args)
+
+		// THEN
+		require.NoError(t, err)
+		snaps.MatchStandaloneSnapshot(t, result)
+	})
+
+	t.Run("works with markdown output format", func(t *testing.T) {
+		// GIVEN
+		args := []string{
+			"compare-modules",
+			"--config-path", "testdata/config/good.yml",
+			"--output-format", "markdown",
+			"apps",
+		}
+
+		// WHEN
+		result, err := fx.runCmd(args)
+
+		// THEN
+		require.NoError(t, err)
+		snaps.MatchStandaloneSnapshot(t, result)
+	})
+
+	t.Run("works with junit output format", func(t *testing.T) {
+		// GIVEN
+		args := []string{
+			"compare-modules",
+			"--config-path", "testdata/config/good.yml",
+			"--output-format", "junit",
+			"apps",
+		}
+
+		// WHEN
+		result, err := fx.runCmd(args)
+
+		// THEN
+		require.NoError(t, err)
+		snaps.MatchStandaloneSnapshot(t, result)
+	})
+
 	//------------//
 	//  FAILURES  //
 	//------------//
@@ -102,6 +380,107 @@ func TestCompareModulesCmd(t *testing.T) {
 		// WHEN
 		result, err := fx.runCmd(args)
 
+		// THEN
+		require.NoError(t, err)
+		snaps.MatchStandaloneSnapshot(t, result)
+	})
+	t.Run("fails when a diff command fails", func(t *testing.T) {
+		// GIVEN
+		args := []string{
+			"compare-modules",
+			"--config-path", "testdata/config/good.yml",
+			"--stdout-plain",
+			"--include-diffs",
+			"apps-diffs",
+		}
+
+		// WHEN
+		result, err := fx.runCmd(args)
+
+		// THEN
+		require.NoError(t, err)
+		snaps.MatchStandaloneSnapshot(t, result)
+	})
+
+	t.Run("fails for negative number of jobs", func(t *testing.T) {
+		// GIVEN
+		args := []string{
+			"compare-modules",
+			"--config-path", "testdata/config/good.yml",
+			"--jobs", "-1",
+			"apps",
+		}
+
+		// WHEN
+		result, err := fx.runCmd(args)
+
+		// THEN
+		require.NoError(t, err)
+		snaps.MatchStandaloneSnapshot(t, result)
+	})
+
+	t.Run("fails for negative cache max age", func(t *testing.T) {
+		// GIVEN
+		args := []string{
+			"compare-modules",
+			"--config-path", "testdata/config/good.yml",
+			"--include-diffs",
+			"--cache-max-age", "-1h",
+			"apps",
+		}
+
+		// WHEN
+		result, err := fx.runCmd(args)
+
+		// THEN
+		require.NoError(t, err)
+		snaps.MatchStandaloneSnapshot(t, result)
+	})
+
+	t.Run("fails when no comparison is specified", func(t *testing.T) {
+		// GIVEN
+		args := []string{
+			"compare-modules",
+			"--config-path", "testdata/config/good.yml",
+		}
+
+		// WHEN
+		result, err := fx.runCmd(args)
+
+		// THEN
+		require.NoError(t, err)
+		snaps.MatchStandaloneSnapshot(t, result)
+	})
+
+	t.Run("fails when comparisons are specified along with --all", func(t *testing.T) {
+		// GIVEN
+		args := []string{
+			"compare-modules",
+			"--config-path", "testdata/config/good.yml",
+			"--all",
+			"apps",
+		}
+
+		// WHEN
+		result, err := fx.runCmd(args)
+
+		// THEN
+		require.NoError(t, err)
+		snaps.MatchStandaloneSnapshot(t, result)
+	})
+
+	t.Run("fails for unknown comparison", func(t *testing.T) {
+		// GIVEN
+		args := []string{
+			"compare-modules",
+			"--config-path", "testdata/config/good.yml",
+			"apps",
+			"unknown",
+		}
+
+		// WHEN
+		result, err := fx.runCmd(args)
+
 		// THEN
 		require.NoError(t, err)
 		snaps.MatchStandaloneSnapshot(t, result)
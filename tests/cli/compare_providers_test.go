@@ -0,0 +1,182 @@
+package cli
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/gkampitakis/go-snaps/snaps"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompareProvidersCmd(t *testing.T) {
+	fx, err := newFixture()
+	require.NoErrorf(t, err, "error setting up fixture: %s", err)
+
+	defer func() {
+		err := fx.cleanup()
+		require.NoErrorf(t, err, "error cleaning up fixture: %s", err)
+	}()
+
+	//-------------//
+	//  SUCCESSES  //
+	//-------------//
+
+	t.Run("help flag works", func(t *testing.T) {
+		// GIVEN
+		args := []string{
+			"compare-providers",
+			"--help",
+		}
+
+		// WHEN
+		result, err := fx.runCmd(args)
+
+		// THEN
+		require.NoError(t, err)
+		snaps.MatchStandaloneSnapshot(t, result)
+	})
+
+	t.Run("works when providers are in sync", func(t *testing.T) {
+		// GIVEN
+		args := []string{
+			"compare-providers",
+			"--config-path", "testdata/config/providers.yml",
+			"--stdout-plain",
+			"providers-in-sync",
+		}
+
+		// WHEN
+		result, err := fx.runCmd(args)
+
+		// THEN
+		require.NoError(t, err)
+		snaps.MatchStandaloneSnapshot(t, result)
+	})
+
+	t.Run("ignoring missing providers works", func(t *testing.T) {
+		// GIVEN
+		args := []string{
+			"compare-providers",
+			"--config-path", "testdata/config/providers.yml",
+			"--stdout-plain",
+			"--ignore-missing-providers",
+			"providers",
+		}
+
+		// WHEN
+		result, err := fx.runCmd(args)
+
+		// THEN
+		require.NoError(t, err)
+		snaps.MatchStandaloneSnapshot(t, result)
+	})
+
+	t.Run("works with json output format", func(t *testing.T) {
+		// GIVEN
+		args := []string{
+			"compare-providers",
+			"--config-path", "testdata/config/providers.yml",
+			"--output-format", "json",
+			"providers",
+		}
+
+		// WHEN
+		result, err := fx.runCmd(args)
+
+		// THEN
+		require.NoError(t, err)
+		snaps.MatchStandaloneSnapshot(t, result)
+	})
+
+	t.Run("skips comparisons with terragrunt sources when running all", func(t *testing.T) {
+		// GIVEN
+		args := []string{
+			"compare-providers",
+			"--config-path", "testdata/config/terragrunt.yml",
+			"--stdout-plain",
+			"--all",
+		}
+
+		// WHEN
+		result, err := fx.runCmd(args)
+
+		// THEN
+		require.NoError(t, err)
+		snaps.MatchStandaloneSnapshot(t, result)
+	})
+
+	//------------//
+	//  FAILURES  //
+	//------------//
+
+	t.Run("fails when providers are out of sync", func(t *testing.T) {
+		// GIVEN
+		args := []string{
+			"compare-providers",
+			"--config-path", "testdata/config/providers.yml",
+			"--stdout-plain",
+			"providers",
+		}
+
+		// WHEN
+		result, err := fx.runCmd(args)
+
+		// THEN
+		require.NoError(t, err)
+		snaps.MatchStandaloneSnapshot(t, result)
+	})
+
+	t.Run("fails when providers are out of sync with html output format", func(t *testing.T) {
+		// GIVEN
+		htmlOutputPath := filepath.Join(fx.tempDir, "reports", "providers.html")
+		args := []string{
+			"compare-providers",
+			"--config-path", "testdata/config/providers.yml",
+			"--output-format", "html",
+			"--html-output", htmlOutputPath,
+			"providers",
+		}
+
+		// WHEN
+		result, err := fx.runCmd(args)
+
+		// THEN
+		require.NoError(t, err)
+		assert.Contains(t, result, "exit_code: 1\n")
+		assert.FileExists(t, htmlOutputPath)
+	})
+
+	t.Run("fails for unknown comparison", func(t *testing.T) {
+		// GIVEN
+		args := []string{
+			"compare-providers",
+			"--config-path", "testdata/config/providers.yml",
+			"unknown",
+		}
+
+		// WHEN
+		result, err := fx.runCmd(args)
+
+		// THEN
+		require.NoError(t, err)
+		snaps.MatchStandaloneSnapshot(t, result)
+	})
+	t.Run("fails for a comparison with terragrunt sources", func(t *testing.T) {
+		// GIVEN
+		args := []string{
+			"compare-providers",
+			"--config-path", "testdata/config/terragrunt.yml",
+			"--stdout-plain",
+			"terraform",
+			"terragrunt",
+		}
+
+		// WHEN
+		result, err := fx.runCmd(args)
+
+		// THEN
+		require.NoError(t, err)
+		snaps.MatchStandaloneSnapshot(t, result)
+	})
+}
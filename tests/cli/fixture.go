@@ -47,6 +47,8 @@ func (f Fixture) cleanup() error {
 
 func (f Fixture) runCmd(args []string) (string, error) {
 	c := exec.Command(f.binPath, args...)
+	// keeps the default diff cache from leaking across test runs
+	c.Env = append(os.Environ(), "XDG_CACHE_HOME="+filepath.Join(f.tempDir, "cache"))
 
 	var stdoutBuf, stderrBuf bytes.Buffer
 	c.Stdout = &stdoutBuf
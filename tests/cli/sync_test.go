@@ -0,0 +1,115 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/gkampitakis/go-snaps/snaps"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSyncCmd(t *testing.T) {
+	fx, err := newFixture()
+	require.NoErrorf(t, err, "error setting up fixture: %s", err)
+
+	defer func() {
+		err := fx.cleanup()
+		require.NoErrorf(t, err, "error cleaning up fixture: %s", err)
+	}()
+
+	//-------------//
+	//  SUCCESSES  //
+	//-------------//
+
+	t.Run("help flag works", func(t *testing.T) {
+		// GIVEN
+		args := []string{
+			"sync",
+			"--help",
+		}
+
+		// WHEN
+		result, err := fx.runCmd(args)
+
+		// THEN
+		require.NoError(t, err)
+		snaps.MatchStandaloneSnapshot(t, result)
+	})
+
+	t.Run("dry run prints a diff", func(t *testing.T) {
+		// GIVEN
+		args := []string{
+			"sync",
+			"--config-path", "testdata/config/good.yml",
+			"--from", "qa",
+			"--to", "prod",
+			"--dry-run",
+			"apps",
+		}
+
+		// WHEN
+		result, err := fx.runCmd(args)
+
+		// THEN
+		require.NoError(t, err)
+		snaps.MatchStandaloneSnapshot(t, result)
+	})
+
+	t.Run("dry run works for specific modules", func(t *testing.T) {
+		// GIVEN
+		args := []string{
+			"sync",
+			"--config-path", "testdata/config/good.yml",
+			"--from", "qa",
+			"--to", "prod",
+			"--module", "module_b",
+			"--dry-run",
+			"apps",
+		}
+
+		// WHEN
+		result, err := fx.runCmd(args)
+
+		// THEN
+		require.NoError(t, err)
+		snaps.MatchStandaloneSnapshot(t, result)
+	})
+
+	//------------//
+	//  FAILURES  //
+	//------------//
+
+	t.Run("fails for unknown label", func(t *testing.T) {
+		// GIVEN
+		args := []string{
+			"sync",
+			"--config-path", "testdata/config/good.yml",
+			"--from", "qa",
+			"--to", "dev",
+			"--dry-run",
+			"apps",
+		}
+
+		// WHEN
+		result, err := fx.runCmd(args)
+
+		// THEN
+		require.NoError(t, err)
+		snaps.MatchStandaloneSnapshot(t, result)
+	})
+
+	t.Run("fails when labels are not provided", func(t *testing.T) {
+		// GIVEN
+		args := []string{
+			"sync",
+			"--config-path", "testdata/config/good.yml",
+			"apps",
+		}
+
+		// WHEN
+		result, err := fx.runCmd(args)
+
+		// THEN
+		require.NoError(t, err)
+		snaps.MatchStandaloneSnapshot(t, result)
+	})
+}
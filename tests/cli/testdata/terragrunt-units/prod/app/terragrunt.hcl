@@ -0,0 +1,3 @@
+terraform {
+  source = "git@github.com:dhth/infrastructure//modules/applications/app?ref=app-v1.0.0"
+}